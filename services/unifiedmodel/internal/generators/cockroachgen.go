@@ -7,11 +7,23 @@ import (
 	"github.com/redbco/redb-open/services/unifiedmodel/internal/models"
 )
 
-type CockroachGenerator struct{}
+// CockroachGenerator renders SQL DDL for CockroachDB from a UnifiedModel.
+// Config controls identifier-quoting behavior; the zero value applies
+// minimal quoting: only reserved words and names that are not plain
+// lowercase identifiers (e.g. mixed case, spaces) are double-quoted.
+type CockroachGenerator struct {
+	Config GeneratorConfig
+}
+
+// NewCockroachGenerator returns a CockroachGenerator that renders
+// identifiers according to cfg.
+func NewCockroachGenerator(cfg GeneratorConfig) *CockroachGenerator {
+	return &CockroachGenerator{Config: cfg}
+}
 
-func (g *CockroachGenerator) GenerateCreateSchema(schema models.Schema) string {
+func (g *CockroachGenerator) GenerateCreateSchema(schema models.Schema, opts ...GenerateOptions) string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", schema.Name))
+	sb.WriteString(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", quoteIdent(schema.Name, g.Config)))
 	if schema.CharacterSet != "" {
 		sb.WriteString(fmt.Sprintf(" CHARACTER SET %s", schema.CharacterSet))
 	}
@@ -22,9 +34,14 @@ func (g *CockroachGenerator) GenerateCreateSchema(schema models.Schema) string {
 	return sb.String()
 }
 
-func (g *CockroachGenerator) GenerateCreateTable(table models.Table) string {
+func (g *CockroachGenerator) GenerateCreateTable(table models.Table, opts ...GenerateOptions) string {
+	o := firstOption(opts...)
+
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s (\n", table.Schema, table.Name))
+	if o.UseDeclarativeSchemaChanger {
+		sb.WriteString("SET use_declarative_schema_changer = 'unsafe_always';\n")
+	}
+	sb.WriteString(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n", quoteQualifiedIdent(g.Config, table.Schema, table.Name)))
 
 	// Add columns
 	columnDefs := make([]string, 0, len(table.Columns))
@@ -37,7 +54,7 @@ func (g *CockroachGenerator) GenerateCreateTable(table models.Table) string {
 	pkColumns := make([]string, 0)
 	for _, col := range table.Columns {
 		if col.IsPrimaryKey {
-			pkColumns = append(pkColumns, col.Name)
+			pkColumns = append(pkColumns, quoteIdent(col.Name, g.Config))
 		}
 	}
 	if len(pkColumns) > 0 {
@@ -52,7 +69,12 @@ func (g *CockroachGenerator) GenerateCreateTable(table models.Table) string {
 	}
 
 	sb.WriteString(strings.Join(columnDefs, ",\n"))
-	sb.WriteString("\n);")
+	sb.WriteString("\n)")
+
+	if storage, _ := g.generateTableStorageClauses(table); storage != "" {
+		sb.WriteString(storage)
+	}
+	sb.WriteString(";")
 
 	// Add indexes
 	for _, index := range table.Indexes {
@@ -62,9 +84,24 @@ func (g *CockroachGenerator) GenerateCreateTable(table models.Table) string {
 	return sb.String()
 }
 
+// GenerateCreateTableWithWarnings behaves like GenerateCreateTable but also
+// returns any warnings about partition zone configs that need follow-up
+// ALTER PARTITION statements the CREATE TABLE itself cannot express.
+func (g *CockroachGenerator) GenerateCreateTableWithWarnings(table models.Table, opts ...GenerateOptions) (string, []string) {
+	_, warnings := g.generateTableStorageClauses(table)
+	return g.GenerateCreateTable(table, opts...), warnings
+}
+
 func (g *CockroachGenerator) generateColumnDefinition(col models.Column) string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("  %s %s", col.Name, col.DataType.Name))
+	sb.WriteString(fmt.Sprintf("  %s %s", quoteIdent(col.Name, g.Config), col.DataType.Name))
+
+	if computed := generateComputedColumnClause(col); computed != "" {
+		sb.WriteString(computed)
+		// A computed column's value comes entirely from its expression; CRDB
+		// rejects NOT NULL/DEFAULT/COLLATE alongside a generation clause.
+		return sb.String()
+	}
 
 	if !col.IsNullable {
 		sb.WriteString(" NOT NULL")
@@ -74,7 +111,7 @@ func (g *CockroachGenerator) generateColumnDefinition(col models.Column) string
 		if col.DefaultIsFunction {
 			sb.WriteString(fmt.Sprintf(" DEFAULT %s", *col.DefaultValue))
 		} else {
-			sb.WriteString(fmt.Sprintf(" DEFAULT '%s'", *col.DefaultValue))
+			sb.WriteString(fmt.Sprintf(" DEFAULT %s", quoteLiteral(*col.DefaultValue)))
 		}
 	}
 
@@ -88,16 +125,24 @@ func (g *CockroachGenerator) generateColumnDefinition(col models.Column) string
 func (g *CockroachGenerator) generateConstraintDefinition(constraint models.Constraint) string {
 	var sb strings.Builder
 
+	quotedColumns := func(cols []string) string {
+		quoted := make([]string, len(cols))
+		for i, c := range cols {
+			quoted[i] = quoteIdent(c, g.Config)
+		}
+		return strings.Join(quoted, ", ")
+	}
+
 	switch constraint.Type {
 	case "UNIQUE":
-		sb.WriteString(fmt.Sprintf("UNIQUE (%s)", strings.Join(constraint.Columns, ", ")))
+		sb.WriteString(fmt.Sprintf("UNIQUE (%s)", quotedColumns(constraint.Columns)))
 	case "CHECK":
 		sb.WriteString(fmt.Sprintf("CHECK (%s)", constraint.CheckExpression))
 	case "FOREIGN KEY":
 		sb.WriteString(fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
-			strings.Join(constraint.Columns, ", "),
-			constraint.ReferencedTable,
-			strings.Join(constraint.ReferencedColumns, ", ")))
+			quotedColumns(constraint.Columns),
+			quoteIdent(constraint.ReferencedTable, g.Config),
+			quotedColumns(constraint.ReferencedColumns)))
 		if constraint.OnDelete != "" {
 			sb.WriteString(fmt.Sprintf(" ON DELETE %s", constraint.OnDelete))
 		}
@@ -107,7 +152,7 @@ func (g *CockroachGenerator) generateConstraintDefinition(constraint models.Cons
 	}
 
 	if constraint.Name != "" {
-		sb.WriteString(fmt.Sprintf(" CONSTRAINT %s", constraint.Name))
+		sb.WriteString(fmt.Sprintf(" CONSTRAINT %s", quoteIdent(constraint.Name, g.Config)))
 	}
 
 	return sb.String()
@@ -121,14 +166,14 @@ func (g *CockroachGenerator) generateCreateIndex(index models.Index) string {
 	}
 	sb.WriteString("INDEX ")
 	if index.Name != "" {
-		sb.WriteString(index.Name)
+		sb.WriteString(quoteIdent(index.Name, g.Config))
 	}
-	sb.WriteString(fmt.Sprintf(" ON %s.%s (", index.Schema, index.Table))
+	sb.WriteString(fmt.Sprintf(" ON %s (", quoteQualifiedIdent(g.Config, index.Schema, index.Table)))
 
 	// Add index columns
 	colDefs := make([]string, 0, len(index.Columns))
 	for _, col := range index.Columns {
-		colDef := col.ColumnName
+		colDef := quoteIdent(col.ColumnName, g.Config)
 		if col.Order > 0 {
 			colDef += " ASC"
 		} else if col.Order < 0 {
@@ -143,10 +188,15 @@ func (g *CockroachGenerator) generateCreateIndex(index models.Index) string {
 	}
 	sb.WriteString(strings.Join(colDefs, ", "))
 	sb.WriteString(")")
+	sb.WriteString(generateHashShardedClause(index))
 
 	// Add include columns if any
 	if len(index.IncludeColumns) > 0 {
-		sb.WriteString(fmt.Sprintf(" INCLUDE (%s)", strings.Join(index.IncludeColumns, ", ")))
+		includeCols := make([]string, len(index.IncludeColumns))
+		for i, c := range index.IncludeColumns {
+			includeCols[i] = quoteIdent(c, g.Config)
+		}
+		sb.WriteString(fmt.Sprintf(" INCLUDE (%s)", strings.Join(includeCols, ", ")))
 	}
 
 	// Add where clause if any
@@ -158,13 +208,13 @@ func (g *CockroachGenerator) generateCreateIndex(index models.Index) string {
 	return sb.String()
 }
 
-func (g *CockroachGenerator) GenerateCreateEnum(enum models.Enum) string {
+func (g *CockroachGenerator) GenerateCreateEnum(enum models.Enum, opts ...GenerateOptions) string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("CREATE TYPE %s.%s AS ENUM (", enum.Schema, enum.Name))
+	sb.WriteString(fmt.Sprintf("CREATE TYPE %s AS ENUM (", quoteQualifiedIdent(g.Config, enum.Schema, enum.Name)))
 
 	values := make([]string, 0, len(enum.Values))
 	for _, value := range enum.Values {
-		values = append(values, fmt.Sprintf("'%s'", value))
+		values = append(values, quoteLiteral(value))
 	}
 	sb.WriteString(strings.Join(values, ", "))
 	sb.WriteString(");")
@@ -172,14 +222,14 @@ func (g *CockroachGenerator) GenerateCreateEnum(enum models.Enum) string {
 	return sb.String()
 }
 
-func (g *CockroachGenerator) GenerateCreateFunction(function models.Function) string {
+func (g *CockroachGenerator) GenerateCreateFunction(function models.Function, opts ...GenerateOptions) string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("CREATE OR REPLACE FUNCTION %s.%s(", function.Schema, function.Name))
+	sb.WriteString(fmt.Sprintf("CREATE OR REPLACE FUNCTION %s(", quoteQualifiedIdent(g.Config, function.Schema, function.Name)))
 
 	// Add parameters
 	params := make([]string, 0, len(function.Arguments))
 	for _, arg := range function.Arguments {
-		params = append(params, fmt.Sprintf("%s %s", arg.Name, arg.DataType))
+		params = append(params, fmt.Sprintf("%s %s", quoteIdent(arg.Name, g.Config), arg.DataType))
 	}
 	sb.WriteString(strings.Join(params, ", "))
 	sb.WriteString(")")
@@ -195,11 +245,11 @@ func (g *CockroachGenerator) GenerateCreateFunction(function models.Function) st
 	return sb.String()
 }
 
-func (g *CockroachGenerator) GenerateCreateTrigger(trigger models.Trigger) string {
+func (g *CockroachGenerator) GenerateCreateTrigger(trigger models.Trigger, opts ...GenerateOptions) string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("CREATE TRIGGER %s\n", trigger.Name))
+	sb.WriteString(fmt.Sprintf("CREATE TRIGGER %s\n", quoteIdent(trigger.Name, g.Config)))
 	sb.WriteString(fmt.Sprintf("  %s %s\n", trigger.Timing, trigger.Event))
-	sb.WriteString(fmt.Sprintf("  ON %s.%s\n", trigger.Schema, trigger.Table))
+	sb.WriteString(fmt.Sprintf("  ON %s\n", quoteQualifiedIdent(g.Config, trigger.Schema, trigger.Table)))
 	sb.WriteString("  FOR EACH ROW\n")
 	sb.WriteString(trigger.Definition)
 	sb.WriteString(";")
@@ -207,9 +257,9 @@ func (g *CockroachGenerator) GenerateCreateTrigger(trigger models.Trigger) strin
 	return sb.String()
 }
 
-func (g *CockroachGenerator) GenerateCreateSequence(sequence models.Sequence) string {
+func (g *CockroachGenerator) GenerateCreateSequence(sequence models.Sequence, opts ...GenerateOptions) string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %s.%s", sequence.Schema, sequence.Name))
+	sb.WriteString(fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %s", quoteQualifiedIdent(g.Config, sequence.Schema, sequence.Name)))
 
 	if sequence.DataType != "" {
 		sb.WriteString(fmt.Sprintf(" AS %s", sequence.DataType))
@@ -245,36 +295,36 @@ func (g *CockroachGenerator) GenerateCreateSequence(sequence models.Sequence) st
 	return sb.String()
 }
 
-func (g *CockroachGenerator) GenerateCreateExtension(extension models.Extension) string {
-	return fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s SCHEMA %s;", extension.Name, extension.Schema)
+func (g *CockroachGenerator) GenerateCreateExtension(extension models.Extension, opts ...GenerateOptions) string {
+	return fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s SCHEMA %s;", quoteIdent(extension.Name, g.Config), quoteIdent(extension.Schema, g.Config))
 }
 
 func (g *CockroachGenerator) GenerateDropSchema(schema models.Schema) string {
-	return fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE;", schema.Name)
+	return fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE;", quoteIdent(schema.Name, g.Config))
 }
 
 func (g *CockroachGenerator) GenerateDropTable(table models.Table) string {
-	return fmt.Sprintf("DROP TABLE IF EXISTS %s.%s CASCADE;", table.Schema, table.Name)
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE;", quoteQualifiedIdent(g.Config, table.Schema, table.Name))
 }
 
 func (g *CockroachGenerator) GenerateDropEnum(enum models.Enum) string {
-	return fmt.Sprintf("DROP TYPE IF EXISTS %s.%s CASCADE;", enum.Schema, enum.Name)
+	return fmt.Sprintf("DROP TYPE IF EXISTS %s CASCADE;", quoteQualifiedIdent(g.Config, enum.Schema, enum.Name))
 }
 
 func (g *CockroachGenerator) GenerateDropFunction(function models.Function) string {
-	return fmt.Sprintf("DROP FUNCTION IF EXISTS %s.%s CASCADE;", function.Schema, function.Name)
+	return fmt.Sprintf("DROP FUNCTION IF EXISTS %s CASCADE;", quoteQualifiedIdent(g.Config, function.Schema, function.Name))
 }
 
 func (g *CockroachGenerator) GenerateDropTrigger(trigger models.Trigger) string {
-	return fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s.%s CASCADE;", trigger.Name, trigger.Schema, trigger.Table)
+	return fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s CASCADE;", quoteIdent(trigger.Name, g.Config), quoteQualifiedIdent(g.Config, trigger.Schema, trigger.Table))
 }
 
 func (g *CockroachGenerator) GenerateDropSequence(sequence models.Sequence) string {
-	return fmt.Sprintf("DROP SEQUENCE IF EXISTS %s.%s CASCADE;", sequence.Schema, sequence.Name)
+	return fmt.Sprintf("DROP SEQUENCE IF EXISTS %s CASCADE;", quoteQualifiedIdent(g.Config, sequence.Schema, sequence.Name))
 }
 
 func (g *CockroachGenerator) GenerateDropExtension(extension models.Extension) string {
-	return fmt.Sprintf("DROP EXTENSION IF EXISTS %s CASCADE;", extension.Name)
+	return fmt.Sprintf("DROP EXTENSION IF EXISTS %s CASCADE;", quoteIdent(extension.Name, g.Config))
 }
 
 func (g *CockroachGenerator) GenerateCreateTableSQL(table models.Table) (string, error) {
@@ -293,60 +343,158 @@ func (g *CockroachGenerator) GenerateCreateSequenceSQL(seq models.Sequence) (str
 	return g.GenerateCreateSequence(seq), nil
 }
 
-func (g *CockroachGenerator) GenerateSchema(model *models.UnifiedModel) (string, []string, error) {
+// GenerateSchema renders the full CREATE-statement DDL for model, along with
+// any warnings about source features that had to be skipped or adjusted for
+// CockroachDB. Its signature is part of the Generator interface and is
+// relied on by callers outside this package (e.g. the unifiedmodel gRPC
+// handlers); use GenerateSchemaWithReport instead if you also need
+// per-feature telemetry.
+func (g *CockroachGenerator) GenerateSchema(model *models.UnifiedModel, opts ...GenerateOptions) (string, []string, error) {
+	sql, warnings, _, err := g.generateSchema(model, opts...)
+	return sql, warnings, err
+}
+
+// GenerateSchemaWithReport behaves like GenerateSchema but also returns a
+// Report of per-feature telemetry, for callers that want to log or expose a
+// feature-usage summary (e.g. across a fleet of GenerateSchema calls).
+func (g *CockroachGenerator) GenerateSchemaWithReport(model *models.UnifiedModel, opts ...GenerateOptions) (string, []string, *Report, error) {
+	return g.generateSchema(model, opts...)
+}
+
+func (g *CockroachGenerator) generateSchema(model *models.UnifiedModel, opts ...GenerateOptions) (string, []string, *Report, error) {
+	o := firstOption(opts...)
 	var sql strings.Builder
 	warnings := []string{}
+	report := newReport()
 
 	// Add header comment
 	sql.WriteString("-- CockroachDB Schema Generated from UnifiedModel\n\n")
 
 	// Process schemas
 	for _, schema := range model.Schemas {
-		sql.WriteString(g.GenerateCreateSchema(schema))
+		sql.WriteString(g.GenerateCreateSchema(schema, o))
 		sql.WriteString("\n\n")
 	}
 
 	// Process tables
+	caps := g.Capabilities()
 	for _, table := range model.Tables {
-		tableSQL := g.GenerateCreateTable(table)
+		table, stripWarnings := stripUnsupportedIndexFeatures(caps, table)
+		warnings = append(warnings, stripWarnings...)
+		table, stripWarnings = stripUnsupportedTableFeatures(caps, table)
+		warnings = append(warnings, stripWarnings...)
+
+		tableSQL, tableWarnings := g.GenerateCreateTableWithWarnings(table, o)
 		sql.WriteString(tableSQL)
 		sql.WriteString("\n\n")
+		warnings = append(warnings, tableWarnings...)
+		recordTableTelemetry(report, table)
 	}
 
 	// Process enums
-	for _, enum := range model.Enums {
-		sql.WriteString(g.GenerateCreateEnum(enum))
+	enums, stripWarnings := stripUnsupportedEnums(caps, model.Enums)
+	warnings = append(warnings, stripWarnings...)
+	for _, enum := range enums {
+		sql.WriteString(g.GenerateCreateEnum(enum, o))
 		sql.WriteString("\n\n")
+		report.incr("enums.created", 1)
+		report.incr("enums.values", len(enum.Values))
 	}
 
 	// Process functions
-	for _, fn := range model.Functions {
-		fnSQL := g.GenerateCreateFunction(fn)
+	functions, stripWarnings := stripUnsupportedFunctions(caps, model.Functions)
+	warnings = append(warnings, stripWarnings...)
+	for _, fn := range functions {
+		fnSQL := g.GenerateCreateFunction(fn, o)
 		sql.WriteString(fnSQL)
 		sql.WriteString("\n\n")
+		report.incr("functions.created", 1)
 	}
 
 	// Process triggers
-	for _, trigger := range model.Triggers {
-		triggerSQL := g.GenerateCreateTrigger(trigger)
+	triggers, stripWarnings := stripUnsupportedTriggers(caps, model.Triggers)
+	warnings = append(warnings, stripWarnings...)
+	for _, trigger := range triggers {
+		triggerSQL := g.GenerateCreateTrigger(trigger, o)
 		sql.WriteString(triggerSQL)
 		sql.WriteString("\n\n")
+		report.incr("triggers.created", 1)
 	}
 
 	// Process sequences
-	for _, seq := range model.Sequences {
-		seqSQL := g.GenerateCreateSequence(seq)
+	sequences, stripWarnings := stripUnsupportedSequences(caps, model.Sequences)
+	warnings = append(warnings, stripWarnings...)
+	for _, seq := range sequences {
+		seqSQL := g.GenerateCreateSequence(seq, o)
 		sql.WriteString(seqSQL)
 		sql.WriteString("\n\n")
+		report.incr("sequences.created", 1)
+		if seq.Cycle {
+			report.incr("sequences.cycle", 1)
+		}
 	}
 
 	// Process extensions
 	for _, ext := range model.Extensions {
-		sql.WriteString(g.GenerateCreateExtension(ext))
+		sql.WriteString(g.GenerateCreateExtension(ext, o))
 		sql.WriteString("\n\n")
+		report.incr("extensions.created", 1)
 	}
 
-	return sql.String(), warnings, nil
+	return sql.String(), warnings, report, nil
+}
+
+// GenerateSchemaPlan generates the same DDL as GenerateSchema but grouped
+// into transactional batches according to opts, since CockroachDB rejects
+// several DDL combinations inside a single transaction. Each table's CREATE
+// TABLE statement (including its indexes and constraints) is batched with
+// the other tables' unless opts.Online requests one-statement-per-batch
+// semantics; schema, enum, function, trigger, sequence and extension
+// statements are marked standalone.
+func (g *CockroachGenerator) GenerateSchemaPlan(model *models.UnifiedModel, opts ...GenerateOptions) (*SchemaChangePlan, error) {
+	o := firstOption(opts...)
+
+	var statements []statementWithTarget
+	for _, schema := range model.Schemas {
+		statements = append(statements, statementWithTarget{sql: g.GenerateCreateSchema(schema, o), standalone: true})
+	}
+	for _, table := range model.Tables {
+		statements = append(statements, statementWithTarget{sql: g.GenerateCreateTable(table, o)})
+	}
+	for _, enum := range model.Enums {
+		statements = append(statements, statementWithTarget{sql: g.GenerateCreateEnum(enum, o), standalone: true})
+	}
+	for _, fn := range model.Functions {
+		statements = append(statements, statementWithTarget{sql: g.GenerateCreateFunction(fn, o), standalone: true})
+	}
+	for _, trigger := range model.Triggers {
+		statements = append(statements, statementWithTarget{sql: g.GenerateCreateTrigger(trigger, o), standalone: true})
+	}
+	for _, seq := range model.Sequences {
+		statements = append(statements, statementWithTarget{sql: g.GenerateCreateSequence(seq, o), standalone: true})
+	}
+	for _, ext := range model.Extensions {
+		statements = append(statements, statementWithTarget{sql: g.GenerateCreateExtension(ext, o), standalone: true})
+	}
+
+	plan := PlanSchemaChange(statements, o)
+	plan.VerificationQueries = g.GenerateVerificationQueries(model, o)
+	return plan, nil
+}
+
+// GenerateVerificationQueries returns one read-only row-count query per
+// table in model, suitable for confirming a table is reachable before or
+// after a schema change runs. When opts.AsOfInterval is set, each query is
+// wrapped with AS OF SYSTEM TIME so it observes pre-migration state without
+// contending with the migration's own writes.
+func (g *CockroachGenerator) GenerateVerificationQueries(model *models.UnifiedModel, opts ...GenerateOptions) []string {
+	o := firstOption(opts...)
+	queries := make([]string, 0, len(model.Tables))
+	for _, table := range model.Tables {
+		qualified := quoteQualifiedIdent(g.Config, table.Schema, table.Name)
+		queries = append(queries, asOfSystemTime(fmt.Sprintf("SELECT count(*) FROM %s", qualified), o))
+	}
+	return queries
 }
 
 func (g *CockroachGenerator) GenerateCreateStatements(schema interface{}) ([]string, error) {
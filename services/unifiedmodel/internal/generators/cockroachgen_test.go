@@ -0,0 +1,73 @@
+package generators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/redbco/redb-open/services/unifiedmodel/internal/models"
+)
+
+func TestStripUnsupportedEnumsSequencesTriggersFunctions(t *testing.T) {
+	caps := Capabilities{}
+
+	enums, warnings := stripUnsupportedEnums(caps, []models.Enum{{Schema: "public", Name: "status"}})
+	if enums != nil {
+		t.Errorf("expected enums to be dropped, got %+v", enums)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("expected one skip warning for the dropped enum, got %v", warnings)
+	}
+
+	functions, warnings := stripUnsupportedFunctions(caps, []models.Function{{Schema: "public", Name: "touch_updated_at"}})
+	if functions != nil {
+		t.Errorf("expected functions to be dropped, got %+v", functions)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("expected one skip warning for the dropped function, got %v", warnings)
+	}
+
+	triggers, warnings := stripUnsupportedTriggers(caps, []models.Trigger{{Schema: "public", Table: "widgets", Name: "widgets_touch"}})
+	if triggers != nil {
+		t.Errorf("expected triggers to be dropped, got %+v", triggers)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("expected one skip warning for the dropped trigger, got %v", warnings)
+	}
+
+	sequences, warnings := stripUnsupportedSequences(caps, []models.Sequence{{Schema: "public", Name: "widget_ids"}})
+	if sequences != nil {
+		t.Errorf("expected sequences to be dropped, got %+v", sequences)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("expected one skip warning for the dropped sequence, got %v", warnings)
+	}
+
+	fullCaps := Capabilities{Enums: true, Functions: true, Triggers: true, Sequences: true}
+	enums, warnings = stripUnsupportedEnums(fullCaps, []models.Enum{{Schema: "public", Name: "status"}})
+	if len(enums) != 1 || warnings != nil {
+		t.Errorf("expected enums to pass through unchanged when supported, got enums=%+v warnings=%v", enums, warnings)
+	}
+}
+
+func TestGenerateSchemaKeepsSupportedStandaloneObjects(t *testing.T) {
+	g := &CockroachGenerator{}
+	model := &models.UnifiedModel{
+		Enums:     []models.Enum{{Schema: "public", Name: "status", Values: []string{"active"}}},
+		Functions: []models.Function{{Schema: "public", Name: "touch_updated_at", Definition: "BEGIN END"}},
+		Triggers:  []models.Trigger{{Schema: "public", Table: "widgets", Name: "widgets_touch", Definition: "EXECUTE FUNCTION touch_updated_at()"}},
+		Sequences: []models.Sequence{{Schema: "public", Name: "widget_ids"}},
+	}
+
+	sql, warnings, _, err := g.generateSchema(model)
+	if err != nil {
+		t.Fatalf("generateSchema() error = %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings when every capability is supported, got %v", warnings)
+	}
+	for _, wanted := range []string{"CREATE TYPE", "CREATE OR REPLACE FUNCTION", "CREATE TRIGGER", "CREATE SEQUENCE"} {
+		if !strings.Contains(sql, wanted) {
+			t.Errorf("expected a %s statement from CockroachGenerator, which declares support for all of these, got SQL:\n%s", wanted, sql)
+		}
+	}
+}
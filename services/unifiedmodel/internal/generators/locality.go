@@ -0,0 +1,117 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redbco/redb-open/services/unifiedmodel/internal/models"
+)
+
+// generateTableStorageClauses renders the CockroachDB-specific clauses that
+// follow a CREATE TABLE's column list: LOCALITY, PARTITION BY, and the
+// row-level TTL WITH (...) clause. It also returns a warning for each
+// partition zone config, which needs a separate ALTER PARTITION statement.
+func (g *CockroachGenerator) generateTableStorageClauses(table models.Table) (string, []string) {
+	var sb strings.Builder
+	var warnings []string
+
+	if table.Partitioning != nil {
+		clause, w := g.generatePartitionClause(table)
+		sb.WriteString(clause)
+		warnings = append(warnings, w...)
+	}
+
+	if table.Locality != nil {
+		sb.WriteString(" ")
+		sb.WriteString(g.generateLocalityClause(*table.Locality))
+	}
+
+	if table.TTL != nil {
+		sb.WriteString(" WITH (")
+		opts := make([]string, 0, 2)
+		if table.TTL.ExpireAfter != "" {
+			opts = append(opts, fmt.Sprintf("ttl_expire_after = %s", quoteLiteral(table.TTL.ExpireAfter)))
+		}
+		if table.TTL.JobCron != "" {
+			opts = append(opts, fmt.Sprintf("ttl_job_cron = %s", quoteLiteral(table.TTL.JobCron)))
+		}
+		sb.WriteString(strings.Join(opts, ", "))
+		sb.WriteString(")")
+	}
+
+	return sb.String(), warnings
+}
+
+func (g *CockroachGenerator) generateLocalityClause(locality models.TableLocality) string {
+	switch strings.ToUpper(locality.Type) {
+	case "REGIONAL BY ROW":
+		if locality.RegionColumn != "" && locality.RegionColumn != "crdb_region" {
+			return fmt.Sprintf("LOCALITY REGIONAL BY ROW AS %s", quoteIdent(locality.RegionColumn, g.Config))
+		}
+		return "LOCALITY REGIONAL BY ROW"
+	case "REGIONAL BY TABLE":
+		if locality.Region != "" {
+			return fmt.Sprintf("LOCALITY REGIONAL BY TABLE IN %s", quoteLiteral(locality.Region))
+		}
+		return "LOCALITY REGIONAL BY TABLE"
+	case "GLOBAL":
+		return "LOCALITY GLOBAL"
+	default:
+		return fmt.Sprintf("LOCALITY %s", locality.Type)
+	}
+}
+
+// generatePartitionClause renders PARTITION BY LIST/RANGE (...) followed by
+// one sub-clause per partition. A partition's zone configuration cannot be
+// expressed inline, so each one is returned as a warning for the caller to
+// apply as a separate ALTER PARTITION ... CONFIGURE ZONE statement.
+func (g *CockroachGenerator) generatePartitionClause(table models.Table) (string, []string) {
+	p := table.Partitioning
+	var warnings []string
+
+	cols := make([]string, len(p.Columns))
+	for i, c := range p.Columns {
+		cols[i] = quoteIdent(c, g.Config)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(" PARTITION BY %s (%s) (", strings.ToUpper(p.Type), strings.Join(cols, ", ")))
+
+	parts := make([]string, 0, len(p.Partitions))
+	for _, part := range p.Partitions {
+		switch strings.ToUpper(p.Type) {
+		case "LIST":
+			parts = append(parts, fmt.Sprintf("PARTITION %s VALUES IN (%s)", quoteIdent(part.Name, g.Config), part.Values))
+		case "RANGE":
+			parts = append(parts, fmt.Sprintf("PARTITION %s VALUES FROM (%s) TO (%s)", quoteIdent(part.Name, g.Config), part.From, part.To))
+		}
+		if part.Zone != "" {
+			warnings = append(warnings, fmt.Sprintf("partition %s needs a separate ALTER PARTITION %s OF TABLE %s.%s CONFIGURE ZONE USING %s", part.Name, part.Name, table.Schema, table.Name, part.Zone))
+		}
+	}
+	sb.WriteString(strings.Join(parts, ", "))
+	sb.WriteString(")")
+
+	return sb.String(), warnings
+}
+
+// generateHashShardedClause renders the USING HASH WITH BUCKET_COUNT clause
+// for a hash-sharded index, or "" if the index isn't hash-sharded.
+func generateHashShardedClause(index models.Index) string {
+	if index.HashSharded == nil || index.HashSharded.BucketCount <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" USING HASH WITH BUCKET_COUNT = %d", index.HashSharded.BucketCount)
+}
+
+// generateComputedColumnClause renders the AS (expr) STORED/VIRTUAL suffix
+// for a computed column, or "" if col isn't computed.
+func generateComputedColumnClause(col models.Column) string {
+	if col.Computed == nil || col.Computed.Expression == "" {
+		return ""
+	}
+	if !col.Computed.Stored {
+		return fmt.Sprintf(" AS (%s) VIRTUAL", col.Computed.Expression)
+	}
+	return fmt.Sprintf(" AS (%s) STORED", col.Computed.Expression)
+}
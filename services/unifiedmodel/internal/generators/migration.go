@@ -0,0 +1,740 @@
+package generators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redbco/redb-open/services/unifiedmodel/internal/models"
+)
+
+// MigrationStep describes a single forward schema-change statement together
+// with the statement that undoes it, so that a migration can be rolled
+// forward or backward one step at a time.
+type MigrationStep struct {
+	Description string
+	SQL         string
+	DownSQL     string
+	Reversible  bool
+	Warnings    []string
+}
+
+// MigrationPlan is the ordered result of diffing two UnifiedModel snapshots.
+// Up is applied in order to move from Old to New; Down undoes Up and must be
+// applied in reverse order (last step first) to move back from New to Old.
+type MigrationPlan struct {
+	Up       []MigrationStep
+	Down     []MigrationStep
+	Warnings []string
+}
+
+// GenerateMigration diffs oldModel against newModel and produces an ordered,
+// reversible migration plan of CREATE/ALTER/DROP statements. Statements are
+// topologically ordered so that tables are created after the tables they
+// reference via foreign keys, and dropped before them.
+func (g *CockroachGenerator) GenerateMigration(oldModel, newModel *models.UnifiedModel) (*MigrationPlan, error) {
+	if oldModel == nil || newModel == nil {
+		return nil, fmt.Errorf("cockroachgen: both old and new models are required")
+	}
+
+	plan := &MigrationPlan{}
+
+	addedTables, removedTables, renamedTables, commonTables := diffTables(oldModel.Tables, newModel.Tables)
+
+	oldByName := tablesByName(oldModel.Tables)
+	newByName := tablesByName(newModel.Tables)
+
+	// Renames first: cheap, reversible, and must happen before column/index
+	// diffing on the common table so both sides are compared under the same
+	// name. Sorted by old name so step order is deterministic across runs.
+	renameFrom := make([]string, 0, len(renamedTables))
+	for oldName := range renamedTables {
+		renameFrom = append(renameFrom, oldName)
+	}
+	sort.Strings(renameFrom)
+	renamedFrom := make(map[string]string, len(renamedTables)) // new name -> old name
+	for _, oldName := range renameFrom {
+		newName := renamedTables[oldName]
+		oldQualified := quoteQualifiedIdent(g.Config, oldByName[oldName].Schema, oldName)
+		newQualified := quoteQualifiedIdent(g.Config, newByName[newName].Schema, newName)
+		plan.addStep(MigrationStep{
+			Description: fmt.Sprintf("rename table %s to %s", oldName, newName),
+			SQL:         fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", oldQualified, newName),
+			DownSQL:     fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", newQualified, oldName),
+			Reversible:  true,
+		})
+		commonTables = append(commonTables, newName)
+		renamedFrom[newName] = oldName
+	}
+
+	// New tables are created in dependency order (referenced table first).
+	for _, table := range orderTablesByDependency(addedTables) {
+		step := MigrationStep{
+			Description: fmt.Sprintf("create table %s.%s", table.Schema, table.Name),
+			SQL:         g.GenerateCreateTable(table),
+			DownSQL:     g.GenerateDropTable(table),
+			Reversible:  true,
+		}
+		plan.addStep(step)
+	}
+
+	// Existing tables are diffed column-by-column, index-by-index.
+	for _, name := range commonTables {
+		oldName := name
+		if from, ok := renamedFrom[name]; ok {
+			oldName = from
+		}
+		oldTable, ok1 := oldByName[oldName]
+		newTable, ok2 := newByName[name]
+		if !ok1 || !ok2 {
+			continue
+		}
+		oldTable.Name = name
+		steps := g.diffTable(oldTable, newTable)
+		for _, s := range steps {
+			plan.addStep(s)
+		}
+	}
+
+	// Removed tables are dropped in reverse dependency order (dependents first).
+	dropOrder := orderTablesByDependency(removedTables)
+	for i := len(dropOrder) - 1; i >= 0; i-- {
+		table := dropOrder[i]
+		plan.addStep(MigrationStep{
+			Description: fmt.Sprintf("drop table %s.%s", table.Schema, table.Name),
+			SQL:         g.GenerateDropTable(table),
+			DownSQL:     g.GenerateCreateTable(table),
+			Reversible:  true,
+			Warnings:    []string{fmt.Sprintf("dropping table %s.%s is a destructive, data-losing operation", table.Schema, table.Name)},
+		})
+	}
+
+	for _, enum := range diffEnums(g, oldModel.Enums, newModel.Enums) {
+		plan.addStep(enum)
+	}
+
+	for _, seq := range diffSequences(g, oldModel.Sequences, newModel.Sequences) {
+		plan.addStep(seq)
+	}
+
+	for _, fn := range diffFunctions(g, oldModel.Functions, newModel.Functions) {
+		plan.addStep(fn)
+	}
+
+	return plan, nil
+}
+
+func (p *MigrationPlan) addStep(step MigrationStep) {
+	downStep := step
+	downStep.SQL, downStep.DownSQL = step.DownSQL, step.SQL
+	p.Up = append(p.Up, step)
+	p.Down = append(p.Down, downStep)
+	p.Warnings = append(p.Warnings, step.Warnings...)
+}
+
+// diffTable compares two versions of the same table and returns the steps
+// needed to turn oldTable into newTable (and back).
+func (g *CockroachGenerator) diffTable(oldTable, newTable models.Table) []MigrationStep {
+	var steps []MigrationStep
+
+	oldCols := columnsByName(oldTable.Columns)
+	newCols := columnsByName(newTable.Columns)
+	qualified := quoteQualifiedIdent(g.Config, newTable.Schema, newTable.Name)
+
+	for _, col := range newTable.Columns {
+		if _, existed := oldCols[col.Name]; !existed {
+			steps = append(steps, MigrationStep{
+				Description: fmt.Sprintf("add column %s.%s.%s", newTable.Schema, newTable.Name, col.Name),
+				SQL:         fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", qualified, g.generateColumnDefinition(col)),
+				DownSQL:     fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", qualified, quoteIdent(col.Name, g.Config)),
+				Reversible:  true,
+			})
+		}
+	}
+
+	for _, col := range oldTable.Columns {
+		if _, stillExists := newCols[col.Name]; !stillExists {
+			steps = append(steps, MigrationStep{
+				Description: fmt.Sprintf("drop column %s.%s.%s", oldTable.Schema, oldTable.Name, col.Name),
+				SQL:         fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", qualified, quoteIdent(col.Name, g.Config)),
+				DownSQL:     fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", qualified, g.generateColumnDefinition(col)),
+				Reversible:  false,
+				Warnings:    []string{fmt.Sprintf("dropping column %s.%s.%s loses data; the down migration recreates the column but not its values", oldTable.Schema, oldTable.Name, col.Name)},
+			})
+		}
+	}
+
+	colNames := make([]string, 0, len(newCols))
+	for name := range newCols {
+		colNames = append(colNames, name)
+	}
+	sort.Strings(colNames)
+	for _, name := range colNames {
+		oldCol, existed := oldCols[name]
+		if !existed {
+			continue
+		}
+		steps = append(steps, g.diffColumn(oldTable, oldCol, newCols[name])...)
+	}
+
+	steps = append(steps, g.diffIndexes(oldTable, newTable)...)
+
+	steps = append(steps, g.diffTableStorage(oldTable, newTable)...)
+
+	return steps
+}
+
+// diffTableStorage detects changes to a table's CockroachDB-native storage
+// directives (LOCALITY, PARTITION BY, row-level TTL). These don't correspond
+// to a column or index, so diffTable's column/index diffing above would
+// otherwise miss a migration that only changes one of them.
+func (g *CockroachGenerator) diffTableStorage(oldTable, newTable models.Table) []MigrationStep {
+	var steps []MigrationStep
+	qualified := quoteQualifiedIdent(g.Config, newTable.Schema, newTable.Name)
+
+	if !localityEqual(oldTable.Locality, newTable.Locality) {
+		steps = append(steps, MigrationStep{
+			Description: fmt.Sprintf("alter locality %s.%s", newTable.Schema, newTable.Name),
+			SQL:         fmt.Sprintf("ALTER TABLE %s %s;", qualified, localityAlterClause(g, newTable.Locality)),
+			DownSQL:     fmt.Sprintf("ALTER TABLE %s %s;", qualified, localityAlterClause(g, oldTable.Locality)),
+			Reversible:  true,
+		})
+	}
+
+	if !partitioningEqual(oldTable.Partitioning, newTable.Partitioning) {
+		newClause, newWarnings := partitionAlterClause(g, newTable)
+		oldClause, _ := partitionAlterClause(g, oldTable)
+		steps = append(steps, MigrationStep{
+			Description: fmt.Sprintf("alter partitioning %s.%s", newTable.Schema, newTable.Name),
+			SQL:         fmt.Sprintf("ALTER TABLE %s %s;", qualified, newClause),
+			DownSQL:     fmt.Sprintf("ALTER TABLE %s %s;", qualified, oldClause),
+			Reversible:  true,
+			Warnings:    newWarnings,
+		})
+	}
+
+	if !ttlEqual(oldTable.TTL, newTable.TTL) {
+		steps = append(steps, MigrationStep{
+			Description: fmt.Sprintf("alter row-level ttl %s.%s", newTable.Schema, newTable.Name),
+			SQL:         ttlAlterSQL(qualified, newTable.TTL),
+			DownSQL:     ttlAlterSQL(qualified, oldTable.TTL),
+			Reversible:  true,
+		})
+	}
+
+	return steps
+}
+
+// localityAlterClause renders the SET LOCALITY ... clause for an ALTER TABLE
+// statement. A nil locality resets the table to CRDB's REGIONAL BY TABLE
+// default, since CRDB tables always have some locality once multi-region is
+// enabled on the database.
+func localityAlterClause(g *CockroachGenerator, locality *models.TableLocality) string {
+	if locality == nil {
+		return "SET LOCALITY REGIONAL BY TABLE"
+	}
+	return "SET " + g.generateLocalityClause(*locality)
+}
+
+// partitionAlterClause renders the PARTITION BY ... clause for an ALTER
+// TABLE statement, or PARTITION BY NOTHING to remove partitioning entirely.
+func partitionAlterClause(g *CockroachGenerator, table models.Table) (string, []string) {
+	if table.Partitioning == nil {
+		return "PARTITION BY NOTHING", nil
+	}
+	clause, warnings := g.generatePartitionClause(table)
+	return strings.TrimSpace(clause), warnings
+}
+
+// ttlAlterSQL renders the statement that sets or clears a table's row-level
+// TTL storage parameters.
+func ttlAlterSQL(qualified string, ttl *models.TableTTL) string {
+	if ttl == nil {
+		return fmt.Sprintf("ALTER TABLE %s RESET (ttl);", qualified)
+	}
+	opts := make([]string, 0, 2)
+	if ttl.ExpireAfter != "" {
+		opts = append(opts, fmt.Sprintf("ttl_expire_after = %s", quoteLiteral(ttl.ExpireAfter)))
+	}
+	if ttl.JobCron != "" {
+		opts = append(opts, fmt.Sprintf("ttl_job_cron = %s", quoteLiteral(ttl.JobCron)))
+	}
+	return fmt.Sprintf("ALTER TABLE %s SET (%s);", qualified, strings.Join(opts, ", "))
+}
+
+func localityEqual(a, b *models.TableLocality) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func ttlEqual(a, b *models.TableTTL) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func partitioningEqual(a, b *models.TablePartitioning) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Type != b.Type || len(a.Partitions) != len(b.Partitions) {
+		return false
+	}
+	if len(a.Columns) != len(b.Columns) {
+		return false
+	}
+	for i := range a.Columns {
+		if a.Columns[i] != b.Columns[i] {
+			return false
+		}
+	}
+	for i := range a.Partitions {
+		if a.Partitions[i] != b.Partitions[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// diffColumn detects type, nullability, and default changes on a column that
+// exists on both sides. CRDB cannot add a primary key via ADD COLUMN, so
+// primary-key changes are routed through ALTER PRIMARY KEY instead.
+func (g *CockroachGenerator) diffColumn(table models.Table, oldCol, newCol models.Column) []MigrationStep {
+	var steps []MigrationStep
+	qualified := quoteQualifiedIdent(g.Config, table.Schema, table.Name)
+	col := quoteIdent(newCol.Name, g.Config)
+
+	if oldCol.DataType.Name != newCol.DataType.Name {
+		warnings := []string{}
+		if isNarrowingTypeChange(oldCol.DataType.Name, newCol.DataType.Name) {
+			warnings = append(warnings, fmt.Sprintf("%s.%s: narrowing %s to %s may truncate existing data", table.Name, newCol.Name, oldCol.DataType.Name, newCol.DataType.Name))
+		}
+		steps = append(steps, MigrationStep{
+			Description: fmt.Sprintf("alter column type %s.%s", table.Name, newCol.Name),
+			SQL:         fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s;", qualified, col, newCol.DataType.Name),
+			DownSQL:     fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s;", qualified, col, oldCol.DataType.Name),
+			Reversible:  len(warnings) == 0,
+			Warnings:    warnings,
+		})
+	}
+
+	if oldCol.IsNullable != newCol.IsNullable {
+		if newCol.IsNullable {
+			steps = append(steps, MigrationStep{
+				Description: fmt.Sprintf("drop not null %s.%s", table.Name, newCol.Name),
+				SQL:         fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", qualified, col),
+				DownSQL:     fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", qualified, col),
+				Reversible:  true,
+			})
+		} else {
+			steps = append(steps, MigrationStep{
+				Description: fmt.Sprintf("set not null %s.%s", table.Name, newCol.Name),
+				SQL:         fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", qualified, col),
+				DownSQL:     fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", qualified, col),
+				Reversible:  true,
+				Warnings:    []string{fmt.Sprintf("%s.%s: SET NOT NULL fails if any existing row is NULL", table.Name, newCol.Name)},
+			})
+		}
+	}
+
+	oldPK, newPK := oldCol.IsPrimaryKey, newCol.IsPrimaryKey
+	if oldPK != newPK {
+		// CRDB cannot add/drop a primary key column via ADD COLUMN; it requires
+		// the dedicated ALTER PRIMARY KEY form.
+		change := "include"
+		if !newPK {
+			change = "exclude"
+		}
+		steps = append(steps, MigrationStep{
+			Description: fmt.Sprintf("alter primary key to %s %s.%s", change, table.Name, newCol.Name),
+			SQL:         fmt.Sprintf("ALTER TABLE %s ALTER PRIMARY KEY USING COLUMNS (%s);", qualified, primaryKeyColumnList(g.Config, table, newCol, newPK)),
+			DownSQL:     fmt.Sprintf("ALTER TABLE %s ALTER PRIMARY KEY USING COLUMNS (%s);", qualified, primaryKeyColumnList(g.Config, table, oldCol, oldPK)),
+			Reversible:  true,
+			Warnings:    []string{"ALTER PRIMARY KEY rewrites the table and all secondary indexes under the hood"},
+		})
+	}
+
+	return steps
+}
+
+func primaryKeyColumnList(cfg GeneratorConfig, table models.Table, changed models.Column, changedIsPK bool) string {
+	cols := make([]string, 0, len(table.Columns))
+	for _, c := range table.Columns {
+		name := c.Name
+		isPK := c.IsPrimaryKey
+		if name == changed.Name {
+			isPK = changedIsPK
+		}
+		if isPK {
+			cols = append(cols, quoteIdent(name, cfg))
+		}
+	}
+	return strings.Join(cols, ", ")
+}
+
+func (g *CockroachGenerator) diffIndexes(oldTable, newTable models.Table) []MigrationStep {
+	var steps []MigrationStep
+	oldIdx := indexesByName(oldTable.Indexes)
+	newIdx := indexesByName(newTable.Indexes)
+
+	newNames := make([]string, 0, len(newIdx))
+	for name := range newIdx {
+		newNames = append(newNames, name)
+	}
+	sort.Strings(newNames)
+	for _, name := range newNames {
+		if _, existed := oldIdx[name]; !existed {
+			steps = append(steps, MigrationStep{
+				Description: fmt.Sprintf("create index %s", name),
+				SQL:         g.generateCreateIndex(newIdx[name]),
+				DownSQL:     fmt.Sprintf("DROP INDEX IF EXISTS %s CASCADE;", quoteQualifiedIdent(g.Config, newTable.Schema, name)),
+				Reversible:  true,
+			})
+		}
+	}
+	oldNames := make([]string, 0, len(oldIdx))
+	for name := range oldIdx {
+		oldNames = append(oldNames, name)
+	}
+	sort.Strings(oldNames)
+	for _, name := range oldNames {
+		if _, stillExists := newIdx[name]; !stillExists {
+			steps = append(steps, MigrationStep{
+				Description: fmt.Sprintf("drop index %s", name),
+				SQL:         fmt.Sprintf("DROP INDEX IF EXISTS %s CASCADE;", quoteQualifiedIdent(g.Config, oldTable.Schema, name)),
+				DownSQL:     g.generateCreateIndex(oldIdx[name]),
+				Reversible:  true,
+			})
+		}
+	}
+	return steps
+}
+
+// diffEnums handles both value additions (online in CRDB) and removals,
+// which require a rewrite and are therefore reported as non-reversible. It
+// takes g so generated CREATE TYPE statements honor the caller's quoting
+// config instead of a zero-value generator's defaults.
+func diffEnums(g *CockroachGenerator, oldEnums, newEnums []models.Enum) []MigrationStep {
+	var steps []MigrationStep
+	oldByName := make(map[string]models.Enum, len(oldEnums))
+	for _, e := range oldEnums {
+		oldByName[e.Name] = e
+	}
+	newByName := make(map[string]models.Enum, len(newEnums))
+	for _, e := range newEnums {
+		newByName[e.Name] = e
+	}
+
+	newNames := make([]string, 0, len(newByName))
+	for name := range newByName {
+		newNames = append(newNames, name)
+	}
+	sort.Strings(newNames)
+	for _, name := range newNames {
+		newEnum := newByName[name]
+		oldEnum, existed := oldByName[name]
+		if !existed {
+			steps = append(steps, MigrationStep{
+				Description: fmt.Sprintf("create enum %s.%s", newEnum.Schema, newEnum.Name),
+				SQL:         g.GenerateCreateEnum(newEnum),
+				DownSQL:     fmt.Sprintf("DROP TYPE IF EXISTS %s CASCADE;", quoteQualifiedIdent(g.Config, newEnum.Schema, newEnum.Name)),
+				Reversible:  true,
+			})
+			continue
+		}
+		oldValues := make(map[string]bool, len(oldEnum.Values))
+		for _, v := range oldEnum.Values {
+			oldValues[v] = true
+		}
+		for _, v := range newEnum.Values {
+			if !oldValues[v] {
+				steps = append(steps, MigrationStep{
+					Description: fmt.Sprintf("add value %q to enum %s", v, newEnum.Name),
+					SQL:         fmt.Sprintf("ALTER TYPE %s ADD VALUE %s;", quoteQualifiedIdent(g.Config, newEnum.Schema, newEnum.Name), quoteLiteral(v)),
+					Reversible:  false,
+					Warnings:    []string{fmt.Sprintf("enum value %q cannot be removed without rewriting %s.%s", v, newEnum.Schema, newEnum.Name)},
+				})
+			}
+		}
+		newValues := make(map[string]bool, len(newEnum.Values))
+		for _, v := range newEnum.Values {
+			newValues[v] = true
+		}
+		for _, v := range oldEnum.Values {
+			if !newValues[v] {
+				steps = append(steps, MigrationStep{
+					Description: fmt.Sprintf("remove value %q from enum %s", v, oldEnum.Name),
+					SQL:         fmt.Sprintf("-- enum value removal requires recreating %s; no online DDL exists for this in CockroachDB", quoteQualifiedIdent(g.Config, oldEnum.Schema, oldEnum.Name)),
+					Reversible:  false,
+					Warnings:    []string{fmt.Sprintf("removing enum value %q requires a full rewrite of %s.%s and is not generated automatically", v, oldEnum.Schema, oldEnum.Name)},
+				})
+			}
+		}
+	}
+	oldNames := make([]string, 0, len(oldByName))
+	for name := range oldByName {
+		oldNames = append(oldNames, name)
+	}
+	sort.Strings(oldNames)
+	for _, name := range oldNames {
+		oldEnum := oldByName[name]
+		if _, stillExists := newByName[name]; !stillExists {
+			steps = append(steps, MigrationStep{
+				Description: fmt.Sprintf("drop enum %s.%s", oldEnum.Schema, oldEnum.Name),
+				SQL:         fmt.Sprintf("DROP TYPE IF EXISTS %s CASCADE;", quoteQualifiedIdent(g.Config, oldEnum.Schema, oldEnum.Name)),
+				DownSQL:     g.GenerateCreateEnum(oldEnum),
+				Reversible:  true,
+			})
+		}
+	}
+	return steps
+}
+
+// diffSequences takes g so generated CREATE SEQUENCE statements honor the
+// caller's quoting config instead of a zero-value generator's defaults.
+func diffSequences(g *CockroachGenerator, oldSeqs, newSeqs []models.Sequence) []MigrationStep {
+	var steps []MigrationStep
+	oldByName := make(map[string]models.Sequence, len(oldSeqs))
+	for _, s := range oldSeqs {
+		oldByName[s.Name] = s
+	}
+	newByName := make(map[string]models.Sequence, len(newSeqs))
+	for _, s := range newSeqs {
+		newByName[s.Name] = s
+	}
+	newNames := make([]string, 0, len(newByName))
+	for name := range newByName {
+		newNames = append(newNames, name)
+	}
+	sort.Strings(newNames)
+	for _, name := range newNames {
+		if _, existed := oldByName[name]; !existed {
+			seq := newByName[name]
+			steps = append(steps, MigrationStep{
+				Description: fmt.Sprintf("create sequence %s.%s", seq.Schema, seq.Name),
+				SQL:         g.GenerateCreateSequence(seq),
+				DownSQL:     g.GenerateDropSequence(seq),
+				Reversible:  true,
+			})
+		}
+	}
+	oldNames := make([]string, 0, len(oldByName))
+	for name := range oldByName {
+		oldNames = append(oldNames, name)
+	}
+	sort.Strings(oldNames)
+	for _, name := range oldNames {
+		if _, stillExists := newByName[name]; !stillExists {
+			seq := oldByName[name]
+			steps = append(steps, MigrationStep{
+				Description: fmt.Sprintf("drop sequence %s.%s", seq.Schema, seq.Name),
+				SQL:         g.GenerateDropSequence(seq),
+				DownSQL:     g.GenerateCreateSequence(seq),
+				Reversible:  true,
+			})
+		}
+	}
+	return steps
+}
+
+func diffFunctions(g *CockroachGenerator, oldFns, newFns []models.Function) []MigrationStep {
+	var steps []MigrationStep
+	oldByName := make(map[string]models.Function, len(oldFns))
+	for _, f := range oldFns {
+		oldByName[f.Name] = f
+	}
+	newByName := make(map[string]models.Function, len(newFns))
+	for _, f := range newFns {
+		newByName[f.Name] = f
+	}
+	newNames := make([]string, 0, len(newByName))
+	for name := range newByName {
+		newNames = append(newNames, name)
+	}
+	sort.Strings(newNames)
+	for _, name := range newNames {
+		fn := newByName[name]
+		old, existed := oldByName[name]
+		if !existed || old.Definition != fn.Definition {
+			steps = append(steps, MigrationStep{
+				Description: fmt.Sprintf("create or replace function %s.%s", fn.Schema, fn.Name),
+				SQL:         g.GenerateCreateFunction(fn),
+				Reversible:  existed,
+				DownSQL: func() string {
+					if existed {
+						return g.GenerateCreateFunction(old)
+					}
+					return g.GenerateDropFunction(fn)
+				}(),
+			})
+		}
+	}
+	oldNames := make([]string, 0, len(oldByName))
+	for name := range oldByName {
+		oldNames = append(oldNames, name)
+	}
+	sort.Strings(oldNames)
+	for _, name := range oldNames {
+		if _, stillExists := newByName[name]; !stillExists {
+			fn := oldByName[name]
+			steps = append(steps, MigrationStep{
+				Description: fmt.Sprintf("drop function %s.%s", fn.Schema, fn.Name),
+				SQL:         g.GenerateDropFunction(fn),
+				DownSQL:     g.GenerateCreateFunction(fn),
+				Reversible:  true,
+			})
+		}
+	}
+	return steps
+}
+
+// diffTables splits newTables/oldTables into added, removed, and renamed
+// sets. A removed table and an added table are treated as a rename when
+// their column signatures (names and types) are identical, since UnifiedModel carries no
+// stable table identifier to track renames directly.
+func diffTables(oldTables, newTables []models.Table) (added, removed []models.Table, renamed map[string]string, common []string) {
+	oldByName := tablesByName(oldTables)
+	newByName := tablesByName(newTables)
+	renamed = make(map[string]string)
+
+	var removedCandidates []models.Table
+	for name, t := range oldByName {
+		if _, ok := newByName[name]; !ok {
+			removedCandidates = append(removedCandidates, t)
+		} else {
+			common = append(common, name)
+		}
+	}
+	sort.Slice(removedCandidates, func(i, j int) bool { return removedCandidates[i].Name < removedCandidates[j].Name })
+
+	var addedCandidates []models.Table
+	for name, t := range newByName {
+		if _, ok := oldByName[name]; !ok {
+			addedCandidates = append(addedCandidates, t)
+		}
+	}
+	sort.Slice(addedCandidates, func(i, j int) bool { return addedCandidates[i].Name < addedCandidates[j].Name })
+
+	// matchedRemoved/renamed are built by iterating the now-sorted candidate
+	// slices above, so rename-source selection is deterministic even when
+	// two removed tables share a column signature.
+	matchedRemoved := make(map[string]bool)
+	for _, newTable := range addedCandidates {
+		renameSource := ""
+		for _, oldTable := range removedCandidates {
+			if matchedRemoved[oldTable.Name] {
+				continue
+			}
+			if columnSignature(oldTable) == columnSignature(newTable) && columnSignature(oldTable) != "" {
+				renameSource = oldTable.Name
+				break
+			}
+		}
+		if renameSource != "" {
+			matchedRemoved[renameSource] = true
+			renamed[renameSource] = newTable.Name
+		} else {
+			added = append(added, newTable)
+		}
+	}
+	for _, oldTable := range removedCandidates {
+		if !matchedRemoved[oldTable.Name] {
+			removed = append(removed, oldTable)
+		}
+	}
+
+	sort.Strings(common)
+	return added, removed, renamed, common
+}
+
+func columnSignature(table models.Table) string {
+	names := make([]string, 0, len(table.Columns))
+	for _, c := range table.Columns {
+		names = append(names, c.Name+":"+c.DataType.Name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func tablesByName(tables []models.Table) map[string]models.Table {
+	m := make(map[string]models.Table, len(tables))
+	for _, t := range tables {
+		m[t.Name] = t
+	}
+	return m
+}
+
+func columnsByName(columns []models.Column) map[string]models.Column {
+	m := make(map[string]models.Column, len(columns))
+	for _, c := range columns {
+		m[c.Name] = c
+	}
+	return m
+}
+
+func indexesByName(indexes []models.Index) map[string]models.Index {
+	m := make(map[string]models.Index, len(indexes))
+	for _, idx := range indexes {
+		m[idx.Name] = idx
+	}
+	return m
+}
+
+// orderTablesByDependency returns tables topologically sorted so that a
+// table referenced by a foreign key appears before the table that
+// references it. Tables are visited in name order; in a cycle (which CRDB
+// allows via deferred FK validation) a foreign key back to a table that is
+// still being visited is ignored, so not every FK in the cycle is honored.
+func orderTablesByDependency(tables []models.Table) []models.Table {
+	byName := make(map[string]models.Table, len(tables))
+	for _, t := range tables {
+		byName[t.Name] = t
+	}
+
+	visited := make(map[string]int) // 0=unvisited, 1=in-progress, 2=done
+	var order []models.Table
+
+	var visit func(name string)
+	visit = func(name string) {
+		table, ok := byName[name]
+		if !ok || visited[name] == 2 || visited[name] == 1 {
+			return
+		}
+		visited[name] = 1
+		for _, c := range table.Constraints {
+			if c.Type == "FOREIGN KEY" && c.ReferencedTable != "" && c.ReferencedTable != name {
+				visit(c.ReferencedTable)
+			}
+		}
+		visited[name] = 2
+		order = append(order, table)
+	}
+
+	names := make([]string, 0, len(tables))
+	for _, t := range tables {
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		visit(name)
+	}
+	return order
+}
+
+func isNarrowingTypeChange(from, to string) bool {
+	widths := map[string]int{
+		"smallint": 1, "int2": 1,
+		"integer": 2, "int": 2, "int4": 2,
+		"bigint": 3, "int8": 3,
+		"varchar": 0, "text": 1,
+	}
+	fromWidth, fromOK := widths[strings.ToLower(from)]
+	toWidth, toOK := widths[strings.ToLower(to)]
+	if !fromOK || !toOK {
+		return from != to
+	}
+	return toWidth < fromWidth
+}
@@ -0,0 +1,274 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/redbco/redb-open/services/unifiedmodel/internal/models"
+)
+
+func intCol(name string, pk bool) models.Column {
+	return models.Column{Name: name, DataType: models.DataType{Name: "int8"}, IsPrimaryKey: pk}
+}
+
+func TestGenerateMigrationAddDropTable(t *testing.T) {
+	g := NewCockroachGenerator(GeneratorConfig{})
+	oldModel := &models.UnifiedModel{
+		Tables: []models.Table{
+			{Schema: "public", Name: "widgets", Columns: []models.Column{intCol("id", true), {Name: "weight", DataType: models.DataType{Name: "int8"}}}},
+		},
+	}
+	newModel := &models.UnifiedModel{
+		Tables: []models.Table{
+			{Schema: "public", Name: "gadgets", Columns: []models.Column{intCol("id", true), {Name: "voltage", DataType: models.DataType{Name: "int8"}}}},
+		},
+	}
+
+	plan, err := g.GenerateMigration(oldModel, newModel)
+	if err != nil {
+		t.Fatalf("GenerateMigration() error = %v", err)
+	}
+
+	var sawCreate, sawDrop bool
+	for _, step := range plan.Up {
+		if strings.Contains(step.SQL, "CREATE TABLE") && strings.Contains(step.SQL, "gadgets") {
+			sawCreate = true
+		}
+		if strings.Contains(step.SQL, "DROP TABLE") && strings.Contains(step.SQL, "widgets") {
+			sawDrop = true
+		}
+	}
+	if !sawCreate {
+		t.Errorf("expected a CREATE TABLE step for gadgets, got steps: %+v", plan.Up)
+	}
+	if !sawDrop {
+		t.Errorf("expected a DROP TABLE step for widgets, got steps: %+v", plan.Up)
+	}
+}
+
+func TestGenerateMigrationRenameTable(t *testing.T) {
+	g := NewCockroachGenerator(GeneratorConfig{})
+	cols := []models.Column{intCol("id", true), {Name: "name", DataType: models.DataType{Name: "text"}}}
+	oldModel := &models.UnifiedModel{
+		Tables: []models.Table{{Schema: "public", Name: "old_name", Columns: cols}},
+	}
+	newModel := &models.UnifiedModel{
+		Tables: []models.Table{{Schema: "public", Name: "new_name", Columns: cols}},
+	}
+
+	plan, err := g.GenerateMigration(oldModel, newModel)
+	if err != nil {
+		t.Fatalf("GenerateMigration() error = %v", err)
+	}
+
+	if len(plan.Up) != 1 {
+		t.Fatalf("expected exactly one step for a pure rename, got %d: %+v", len(plan.Up), plan.Up)
+	}
+	if !strings.Contains(plan.Up[0].SQL, "RENAME TO new_name") {
+		t.Errorf("expected a RENAME TO new_name step, got SQL = %q", plan.Up[0].SQL)
+	}
+	if !plan.Up[0].Reversible {
+		t.Errorf("expected rename step to be reversible")
+	}
+}
+
+func TestDiffColumnTypeNullabilityAndPrimaryKey(t *testing.T) {
+	g := NewCockroachGenerator(GeneratorConfig{})
+	table := models.Table{Schema: "public", Name: "widgets"}
+
+	oldCol := models.Column{Name: "amount", DataType: models.DataType{Name: "int4"}, IsNullable: true}
+	newCol := models.Column{Name: "amount", DataType: models.DataType{Name: "int8"}, IsNullable: false, IsPrimaryKey: true}
+
+	steps := g.diffColumn(table, oldCol, newCol)
+
+	var sawTypeChange, sawNotNull, sawPK bool
+	for _, s := range steps {
+		if strings.Contains(s.SQL, "TYPE int8") {
+			sawTypeChange = true
+		}
+		if strings.Contains(s.SQL, "SET NOT NULL") {
+			sawNotNull = true
+		}
+		if strings.Contains(s.SQL, "ALTER PRIMARY KEY") {
+			sawPK = true
+		}
+	}
+	if !sawTypeChange {
+		t.Errorf("expected a type-change step, got steps: %+v", steps)
+	}
+	if !sawNotNull {
+		t.Errorf("expected a SET NOT NULL step, got steps: %+v", steps)
+	}
+	if !sawPK {
+		t.Errorf("expected an ALTER PRIMARY KEY step, got steps: %+v", steps)
+	}
+}
+
+func TestDiffEnumsAddRemoveValue(t *testing.T) {
+	g := NewCockroachGenerator(GeneratorConfig{})
+	oldEnums := []models.Enum{{Schema: "public", Name: "status", Values: []string{"active", "retired"}}}
+	newEnums := []models.Enum{{Schema: "public", Name: "status", Values: []string{"active", "archived"}}}
+
+	steps := diffEnums(g, oldEnums, newEnums)
+
+	var sawAdd, sawRemove bool
+	for _, s := range steps {
+		if strings.Contains(s.SQL, `ADD VALUE 'archived'`) {
+			sawAdd = true
+		}
+		if strings.Contains(s.Description, `remove value "retired"`) {
+			sawRemove = true
+			if s.Reversible {
+				t.Errorf("removing an enum value should not be marked reversible")
+			}
+		}
+	}
+	if !sawAdd {
+		t.Errorf("expected an ADD VALUE step for 'archived', got steps: %+v", steps)
+	}
+	if !sawRemove {
+		t.Errorf("expected a remove-value step for 'retired', got steps: %+v", steps)
+	}
+}
+
+func TestOrderTablesByDependency(t *testing.T) {
+	tables := []models.Table{
+		{Name: "orders", Constraints: []models.Constraint{
+			{Type: "FOREIGN KEY", ReferencedTable: "customers"},
+		}},
+		{Name: "customers"},
+	}
+
+	ordered := orderTablesByDependency(tables)
+	if len(ordered) != 2 {
+		t.Fatalf("expected 2 tables, got %d", len(ordered))
+	}
+
+	var customersIdx, ordersIdx int
+	for i, tbl := range ordered {
+		switch tbl.Name {
+		case "customers":
+			customersIdx = i
+		case "orders":
+			ordersIdx = i
+		}
+	}
+	if customersIdx > ordersIdx {
+		t.Errorf("expected customers before orders, got order: %v", []string{ordered[0].Name, ordered[1].Name})
+	}
+}
+
+func TestDiffTableIsDeterministicAcrossManyColumns(t *testing.T) {
+	table := models.Table{Schema: "public", Name: "widgets"}
+	oldCols := []models.Column{intCol("id", true)}
+	newCols := []models.Column{intCol("id", true)}
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("col_%d", i)
+		oldCols = append(oldCols, models.Column{Name: name, DataType: models.DataType{Name: "int4"}, IsNullable: true})
+		newCols = append(newCols, models.Column{Name: name, DataType: models.DataType{Name: "int8"}, IsNullable: false})
+	}
+	oldTable := table
+	oldTable.Columns = oldCols
+	newTable := table
+	newTable.Columns = newCols
+
+	g := NewCockroachGenerator(GeneratorConfig{})
+	var first []string
+	for i := 0; i < 10; i++ {
+		steps := g.diffTable(oldTable, newTable)
+		var descriptions []string
+		for _, s := range steps {
+			descriptions = append(descriptions, s.Description)
+		}
+		if first == nil {
+			first = descriptions
+			continue
+		}
+		if strings.Join(first, "|") != strings.Join(descriptions, "|") {
+			t.Fatalf("step order changed across runs:\nrun 1: %v\nrun %d: %v", first, i+1, descriptions)
+		}
+	}
+}
+
+func TestDiffEnumsIsDeterministicAcrossManyEnums(t *testing.T) {
+	var newEnums []models.Enum
+	for i := 0; i < 5; i++ {
+		newEnums = append(newEnums, models.Enum{Schema: "public", Name: fmt.Sprintf("enum_%d", i), Values: []string{"a", "b"}})
+	}
+
+	g := NewCockroachGenerator(GeneratorConfig{})
+	var first []string
+	for i := 0; i < 10; i++ {
+		steps := diffEnums(g, nil, newEnums)
+		var descriptions []string
+		for _, s := range steps {
+			descriptions = append(descriptions, s.Description)
+		}
+		if first == nil {
+			first = descriptions
+			continue
+		}
+		if strings.Join(first, "|") != strings.Join(descriptions, "|") {
+			t.Fatalf("step order changed across runs:\nrun 1: %v\nrun %d: %v", first, i+1, descriptions)
+		}
+	}
+}
+
+func TestDiffEnumsEscapesInjectionAttempt(t *testing.T) {
+	g := NewCockroachGenerator(GeneratorConfig{})
+	oldEnums := []models.Enum{{Schema: "public", Name: "status", Values: []string{"active"}}}
+	newEnums := []models.Enum{{Schema: "public", Name: "status", Values: []string{"active", "b'; DROP TABLE users; --"}}}
+
+	steps := diffEnums(g, oldEnums, newEnums)
+
+	var sawAdd bool
+	for _, s := range steps {
+		if strings.Contains(s.SQL, "ADD VALUE") {
+			sawAdd = true
+			if strings.Contains(s.SQL, "DROP TABLE users") && !strings.Contains(s.SQL, "''") {
+				t.Errorf("enum value escaped unsafely into SQL: %q", s.SQL)
+			}
+		}
+	}
+	if !sawAdd {
+		t.Errorf("expected an ADD VALUE step, got steps: %+v", steps)
+	}
+}
+
+func TestGenerateMigrationIsDeterministic(t *testing.T) {
+	g := NewCockroachGenerator(GeneratorConfig{})
+	colsA := []models.Column{intCol("id", true), {Name: "shared", DataType: models.DataType{Name: "text"}}}
+	colsB := []models.Column{intCol("id", true), {Name: "other_shared", DataType: models.DataType{Name: "text"}}}
+	oldModel := &models.UnifiedModel{
+		Tables: []models.Table{
+			{Schema: "public", Name: "old_a", Columns: colsA},
+			{Schema: "public", Name: "old_b", Columns: colsB},
+		},
+	}
+	newModel := &models.UnifiedModel{
+		Tables: []models.Table{
+			{Schema: "public", Name: "new_a", Columns: colsA},
+			{Schema: "public", Name: "new_b", Columns: colsB},
+		},
+	}
+
+	var first []string
+	for i := 0; i < 10; i++ {
+		plan, err := g.GenerateMigration(oldModel, newModel)
+		if err != nil {
+			t.Fatalf("GenerateMigration() error = %v", err)
+		}
+		var descriptions []string
+		for _, s := range plan.Up {
+			descriptions = append(descriptions, s.Description)
+		}
+		if first == nil {
+			first = descriptions
+			continue
+		}
+		if strings.Join(first, "|") != strings.Join(descriptions, "|") {
+			t.Fatalf("step order changed across runs:\nrun 1: %v\nrun %d: %v", first, i+1, descriptions)
+		}
+	}
+}
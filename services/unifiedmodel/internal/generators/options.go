@@ -0,0 +1,128 @@
+package generators
+
+import "fmt"
+
+// GenerateOptions controls the execution semantics of a schema change,
+// independent of the SQL text itself: whether statements are batched into
+// transactions, whether CRDB's declarative schema changer is requested, and
+// whether verification reads should pin a historical timestamp.
+type GenerateOptions struct {
+	// Online, when true, prefers schema-change strategies that avoid
+	// blocking concurrent reads/writes (e.g. avoiding unsupported
+	// multi-statement transactions) over the shortest SQL.
+	Online bool
+
+	// UseDeclarativeSchemaChanger emits a session variable enabling CRDB's
+	// declarative (online) schema changer for the statements that follow.
+	UseDeclarativeSchemaChanger bool
+
+	// AsOfInterval, if non-empty (e.g. "-10s"), causes verification/read
+	// statements generated alongside a migration to run as of a past system
+	// time, so they observe the pre-migration state without blocking on the
+	// migration's own writes.
+	AsOfInterval string
+}
+
+// DefaultGenerateOptions returns the options used when a caller does not
+// supply any, matching today's behavior: no online-mode batching, no
+// declarative schema changer hint, no AS OF SYSTEM TIME reads.
+func DefaultGenerateOptions() GenerateOptions {
+	return GenerateOptions{}
+}
+
+// firstOption returns the first of a variadic GenerateOptions slice, or the
+// defaults if the caller passed none. Generate* methods take opts
+// variadically so existing call sites compile unchanged while still letting
+// callers opt into online/transactional semantics.
+func firstOption(opts ...GenerateOptions) GenerateOptions {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return DefaultGenerateOptions()
+}
+
+// SchemaChangePlan groups a set of DDL statements into transactional
+// batches. CockroachDB rejects several DDL combinations inside a single
+// transaction (e.g. more than one schema-change statement touching the same
+// table, or an index backfill alongside other DDL), so statements that
+// cannot safely share a transaction are reported as their own batch.
+type SchemaChangePlan struct {
+	Batches  [][]string
+	Warnings []string
+
+	// VerificationQueries are read-only row-count checks, one per table in
+	// the plan's model, for confirming the affected tables are reachable
+	// before/after the plan runs. When GenerateOptions.AsOfInterval is set,
+	// each query is pinned to that historical timestamp via AS OF SYSTEM
+	// TIME so it observes pre-migration state without contending with the
+	// schema change's own writes.
+	VerificationQueries []string
+}
+
+// PlanSchemaChange takes already-generated DDL statements for a set of
+// tables and groups them into transactional batches according to opts.
+// Statements not flagged standalone are batched together; each statement
+// flagged standalone gets its own batch.
+func PlanSchemaChange(statements []statementWithTarget, opts GenerateOptions) *SchemaChangePlan {
+	plan := &SchemaChangePlan{}
+
+	if !opts.Online {
+		// Transactional mode: everything that touches a single table can
+		// share one transaction; cross-table statements run standalone.
+		var txBatch []string
+		for _, stmt := range statements {
+			if stmt.standalone {
+				plan.flush(&txBatch)
+				plan.Batches = append(plan.Batches, []string{stmt.sql})
+				continue
+			}
+			txBatch = append(txBatch, stmt.sql)
+		}
+		plan.flush(&txBatch)
+		return plan
+	}
+
+	// Online mode: CRDB only allows a single schema-change statement per
+	// table per transaction, so each statement becomes its own batch
+	// (auto-commit), which is the safest thing that always works online.
+	for _, stmt := range statements {
+		batch := []string{stmt.sql}
+		if opts.UseDeclarativeSchemaChanger {
+			batch = append([]string{"SET use_declarative_schema_changer = 'unsafe_always';"}, batch...)
+		}
+		plan.Batches = append(plan.Batches, batch)
+		if stmt.standalone {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("statement %q must run standalone even in online mode", stmt.sql))
+		}
+	}
+	return plan
+}
+
+func (p *SchemaChangePlan) flush(batch *[]string) {
+	if len(*batch) == 0 {
+		return
+	}
+	p.Batches = append(p.Batches, append([]string(nil), *batch...))
+	*batch = (*batch)[:0]
+}
+
+// statementWithTarget pairs a generated statement with whether CRDB requires
+// it to run outside any user transaction (auto-commit).
+type statementWithTarget struct {
+	sql        string
+	standalone bool
+}
+
+// asOfSystemTime wraps a read/verification query with AS OF SYSTEM TIME when
+// opts.AsOfInterval is set, so pre-migration state can be validated without
+// contending with the migration's own writes.
+func asOfSystemTime(query string, opts GenerateOptions) string {
+	if opts.AsOfInterval == "" {
+		return query
+	}
+	trimmed := query
+	for len(trimmed) > 0 && trimmed[len(trimmed)-1] == ';' {
+		trimmed = trimmed[:len(trimmed)-1]
+	}
+	return fmt.Sprintf("%s AS OF SYSTEM TIME '%s';", trimmed, opts.AsOfInterval)
+}
@@ -0,0 +1,122 @@
+package generators
+
+import "strings"
+
+// GeneratorConfig controls how a generator renders identifiers and literals.
+// It is construction-time configuration for a generator instance, as
+// opposed to GenerateOptions, which controls the execution semantics of a
+// single GenerateSchema call.
+type GeneratorConfig struct {
+	// QuoteAll, when true, double-quotes every identifier regardless of
+	// whether it needs it. When false (the default), identifiers are only
+	// quoted when required (reserved word, mixed case, or special chars).
+	QuoteAll bool
+
+	// PreserveCase, when true, always quotes mixed- or upper-case
+	// identifiers so CRDB does not fold them to lowercase. Minimal quoting
+	// already quotes such identifiers, so output is the same whether or not
+	// it is set; all-lowercase identifiers stay unquoted unless QuoteAll is
+	// set.
+	PreserveCase bool
+}
+
+// reservedWords is the set of CockroachDB/Postgres reserved keywords that
+// must always be quoted when used as identifiers. It is not exhaustive of
+// every keyword CRDB recognizes, only the ones that are reserved (cannot be
+// used unquoted as an identifier in any context).
+var reservedWords = map[string]bool{
+	"all": true, "analyse": true, "analyze": true, "and": true, "any": true,
+	"array": true, "as": true, "asc": true, "asymmetric": true, "both": true,
+	"case": true, "cast": true, "check": true, "collate": true, "column": true,
+	"constraint": true, "create": true, "current_catalog": true, "current_date": true,
+	"current_role": true, "current_time": true, "current_timestamp": true,
+	"current_user": true, "default": true, "deferrable": true, "desc": true,
+	"distinct": true, "do": true, "else": true, "end": true, "except": true,
+	"false": true, "fetch": true, "for": true, "foreign": true, "from": true,
+	"grant": true, "group": true, "having": true, "in": true, "initially": true,
+	"intersect": true, "into": true, "lateral": true, "leading": true, "limit": true,
+	"localtime": true, "localtimestamp": true, "not": true, "null": true, "offset": true,
+	"on": true, "only": true, "or": true, "order": true, "order_by": true,
+	"placing": true, "primary": true, "references": true, "returning": true,
+	"select": true, "session_user": true, "some": true, "symmetric": true,
+	"table": true, "then": true, "to": true, "trailing": true, "true": true,
+	"union": true, "unique": true, "user": true, "using": true, "variadic": true,
+	"when": true, "where": true, "window": true, "with": true,
+}
+
+func isReservedWord(name string) bool {
+	return reservedWords[strings.ToLower(name)]
+}
+
+// needsQuoting reports whether name must be double-quoted to be used safely
+// as a CRDB/Postgres identifier: it's empty, starts with something other
+// than a lowercase letter or underscore, contains anything other than
+// lowercase letters, digits, underscores and dollar signs, or collides with
+// a reserved word.
+func needsQuoting(name string) bool {
+	if name == "" || isReservedWord(name) {
+		return true
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z':
+		case r == '_':
+		case r >= '0' && r <= '9', r == '$':
+			if i == 0 {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// quoteIdent renders name as a CRDB/Postgres identifier, double-quoting it
+// (and escaping any embedded double quotes) when cfg requires it or the
+// name would otherwise be ambiguous or invalid unquoted. cfg is optional;
+// the zero value applies minimal quoting.
+func quoteIdent(name string, cfg ...GeneratorConfig) string {
+	c := firstConfig(cfg...)
+	if !c.QuoteAll && !(c.PreserveCase && hasUpper(name)) && !needsQuoting(name) {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteQualifiedIdent quotes each dot-separated part of a schema-qualified
+// name independently (e.g. schema.table), rather than quoting the whole
+// string as one identifier.
+func quoteQualifiedIdent(cfg GeneratorConfig, parts ...string) string {
+	quoted := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p == "" {
+			continue
+		}
+		quoted = append(quoted, quoteIdent(p, cfg))
+	}
+	return strings.Join(quoted, ".")
+}
+
+// quoteLiteral renders value as a single-quoted SQL string literal,
+// doubling any embedded single quotes per standard SQL escaping. It never
+// interprets backslash escapes, matching CRDB's default standard_conforming_strings.
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func hasUpper(name string) bool {
+	for _, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			return true
+		}
+	}
+	return false
+}
+
+func firstConfig(cfg ...GeneratorConfig) GeneratorConfig {
+	if len(cfg) > 0 {
+		return cfg[0]
+	}
+	return GeneratorConfig{}
+}
@@ -0,0 +1,102 @@
+package generators
+
+import "testing"
+
+func TestQuoteIdent(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  GeneratorConfig
+		want string
+	}{
+		{name: "users", want: "users"},
+		{name: "user", want: `"user"`},            // reserved word
+		{name: "order", want: `"order"`},          // reserved word
+		{name: "Users", want: `"Users"`},          // mixed case needs quoting
+		{name: "has space", want: `"has space"`},  // whitespace
+		{name: `has"quote`, want: `"has""quote"`}, // embedded quote escaped
+		{name: "plain", cfg: GeneratorConfig{QuoteAll: true}, want: `"plain"`},
+		{name: "plain", cfg: GeneratorConfig{PreserveCase: true}, want: "plain"},
+		{name: "MixedCase", cfg: GeneratorConfig{PreserveCase: true}, want: `"MixedCase"`},
+	}
+	for _, c := range cases {
+		if got := quoteIdent(c.name, c.cfg); got != c.want {
+			t.Errorf("quoteIdent(%q, %+v) = %s, want %s", c.name, c.cfg, got, c.want)
+		}
+	}
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"hello", "'hello'"},
+		{"O'Brien", "'O''Brien'"},
+		{"", "''"},
+	}
+	for _, c := range cases {
+		if got := quoteLiteral(c.in); got != c.want {
+			t.Errorf("quoteLiteral(%q) = %s, want %s", c.in, got, c.want)
+		}
+	}
+}
+
+// FuzzQuoteIdent checks that quoteIdent never panics and always produces a
+// value that round-trips: stripping a leading/trailing quote pair and
+// un-escaping doubled quotes recovers the original name.
+func FuzzQuoteIdent(f *testing.F) {
+	seeds := []string{
+		"users", "user", "order", "Users", "has space",
+		`has"quote`, "select", "'; DROP TABLE users; --", "ẃëird_üñïcödé",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		quoted := quoteIdent(name)
+		if len(quoted) >= 2 && quoted[0] == '"' && quoted[len(quoted)-1] == '"' {
+			inner := quoted[1 : len(quoted)-1]
+			unescaped := unescapeDoubledQuotes(inner)
+			if unescaped != name {
+				t.Errorf("quoteIdent(%q) = %q did not round-trip, got %q", name, quoted, unescaped)
+			}
+		} else if quoted != name {
+			t.Errorf("unquoted result %q does not match input %q", quoted, name)
+		}
+	})
+}
+
+// FuzzQuoteLiteral checks that quoteLiteral never panics and always produces
+// a validly-escaped single-quoted literal.
+func FuzzQuoteLiteral(f *testing.F) {
+	seeds := []string{"hello", "O'Brien", "", "'; DROP TABLE users; --", "multi'''quote"}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		literal := quoteLiteral(value)
+		if len(literal) < 2 || literal[0] != '\'' || literal[len(literal)-1] != '\'' {
+			t.Fatalf("quoteLiteral(%q) = %q is not wrapped in single quotes", value, literal)
+		}
+		inner := literal[1 : len(literal)-1]
+		for i := 0; i < len(inner); i++ {
+			if inner[i] == '\'' {
+				if i+1 >= len(inner) || inner[i+1] != '\'' {
+					t.Fatalf("quoteLiteral(%q) = %q has an unescaped single quote", value, literal)
+				}
+				i++
+			}
+		}
+	})
+}
+
+func unescapeDoubledQuotes(s string) string {
+	out := make([]rune, 0, len(s))
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '"' && i+1 < len(runes) && runes[i+1] == '"' {
+			out = append(out, '"')
+			i++
+			continue
+		}
+		out = append(out, runes[i])
+	}
+	return string(out)
+}
@@ -0,0 +1,245 @@
+package generators
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/redbco/redb-open/services/unifiedmodel/internal/models"
+)
+
+// Generator is the common surface every per-dialect SQL generator in this
+// package implements. Callers that need to target a dialect by name should
+// go through Registry/For rather than constructing a concrete generator
+// directly, so new dialects can be added without touching call sites.
+type Generator interface {
+	GenerateCreateSchema(schema models.Schema, opts ...GenerateOptions) string
+	GenerateCreateTable(table models.Table, opts ...GenerateOptions) string
+	GenerateCreateEnum(enum models.Enum, opts ...GenerateOptions) string
+	GenerateCreateFunction(function models.Function, opts ...GenerateOptions) string
+	GenerateCreateTrigger(trigger models.Trigger, opts ...GenerateOptions) string
+	GenerateCreateSequence(sequence models.Sequence, opts ...GenerateOptions) string
+	GenerateCreateExtension(extension models.Extension, opts ...GenerateOptions) string
+	GenerateDropSchema(schema models.Schema) string
+	GenerateDropTable(table models.Table) string
+	GenerateDropEnum(enum models.Enum) string
+	GenerateDropFunction(function models.Function) string
+	GenerateDropTrigger(trigger models.Trigger) string
+	GenerateDropSequence(sequence models.Sequence) string
+	GenerateDropExtension(extension models.Extension) string
+	GenerateSchema(model *models.UnifiedModel, opts ...GenerateOptions) (string, []string, error)
+	GenerateSchemaWithReport(model *models.UnifiedModel, opts ...GenerateOptions) (string, []string, *Report, error)
+	Capabilities() Capabilities
+}
+
+// Capabilities describes which UnifiedModel features a dialect's generator
+// can render as valid SQL. GenerateSchema consults this to skip a feature
+// with a warning instead of emitting SQL the target database would reject.
+type Capabilities struct {
+	Enums              bool
+	Sequences          bool
+	Triggers           bool
+	Functions          bool
+	PartialIndexes     bool
+	IncludeColumns     bool
+	ComputedColumns    bool
+	HashShardedIndexes bool
+	Locality           bool
+	Partitioning       bool
+	RowLevelTTL        bool
+}
+
+// Capabilities reports the schema features CockroachGenerator can render.
+func (g *CockroachGenerator) Capabilities() Capabilities {
+	return Capabilities{
+		Enums:              true,
+		Sequences:          true,
+		Triggers:           true,
+		Functions:          true,
+		PartialIndexes:     true,
+		IncludeColumns:     true,
+		ComputedColumns:    true,
+		HashShardedIndexes: true,
+		Locality:           true,
+		Partitioning:       true,
+		RowLevelTTL:        true,
+	}
+}
+
+// Registry resolves a Generator by dialect name ("cockroach", "postgres",
+// "mysql", "sqlite", "mssql", ...). It is safe for concurrent use.
+type Registry struct {
+	mu         sync.RWMutex
+	generators map[string]Generator
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{generators: make(map[string]Generator)}
+}
+
+// Register associates dialect with gen, overwriting any previous
+// registration for that dialect.
+func (r *Registry) Register(dialect string, gen Generator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generators[dialect] = gen
+}
+
+// For returns the Generator registered for dialect, or an error if no
+// generator has been registered under that name.
+func (r *Registry) For(dialect string) (Generator, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	gen, ok := r.generators[dialect]
+	if !ok {
+		return nil, fmt.Errorf("generators: no generator registered for dialect %q", dialect)
+	}
+	return gen, nil
+}
+
+// defaultRegistry is populated by the init() below and backs the
+// package-level Register/For helpers.
+var defaultRegistry = NewRegistry()
+
+func init() {
+	defaultRegistry.Register("cockroach", &CockroachGenerator{})
+}
+
+// Register associates dialect with gen in the package's default registry.
+func Register(dialect string, gen Generator) {
+	defaultRegistry.Register(dialect, gen)
+}
+
+// For returns the Generator registered for dialect in the package's default
+// registry, e.g. gen, err := generators.For("cockroach"). It returns an
+// error for an unknown dialect, so its result cannot be chained directly
+// into GenerateSchema.
+func For(dialect string) (Generator, error) {
+	return defaultRegistry.For(dialect)
+}
+
+// stripUnsupportedIndexFeatures drops INCLUDE columns, partial-index WHERE
+// clauses, and hash-sharding from table's indexes when caps says the target
+// dialect can't render them, returning the adjusted table and a warning per
+// stripped feature instead of letting the caller emit invalid SQL.
+func stripUnsupportedIndexFeatures(caps Capabilities, table models.Table) (models.Table, []string) {
+	if caps.IncludeColumns && caps.PartialIndexes && caps.HashShardedIndexes {
+		return table, nil
+	}
+
+	var warnings []string
+	adjusted := make([]models.Index, len(table.Indexes))
+	for i, idx := range table.Indexes {
+		if !caps.IncludeColumns && len(idx.IncludeColumns) > 0 {
+			warnings = append(warnings, fmt.Sprintf("index %s on %s.%s uses INCLUDE columns, which this dialect does not support; they were dropped", idx.Name, table.Schema, table.Name))
+			idx.IncludeColumns = nil
+		}
+		if !caps.PartialIndexes && idx.WhereClause != "" {
+			warnings = append(warnings, fmt.Sprintf("index %s on %s.%s is a partial index, which this dialect does not support; the WHERE clause was dropped", idx.Name, table.Schema, table.Name))
+			idx.WhereClause = ""
+		}
+		if !caps.HashShardedIndexes && idx.HashSharded != nil {
+			warnings = append(warnings, fmt.Sprintf("index %s on %s.%s is hash-sharded, which this dialect does not support; the hash sharding was dropped", idx.Name, table.Schema, table.Name))
+			idx.HashSharded = nil
+		}
+		adjusted[i] = idx
+	}
+	table.Indexes = adjusted
+	return table, warnings
+}
+
+// stripUnsupportedEnums drops enum definitions entirely when caps says the
+// target dialect can't render them, returning the remaining enums and a
+// warning per dropped enum instead of letting the caller emit invalid SQL.
+func stripUnsupportedEnums(caps Capabilities, enums []models.Enum) ([]models.Enum, []string) {
+	if caps.Enums {
+		return enums, nil
+	}
+	var warnings []string
+	for _, e := range enums {
+		warnings = append(warnings, fmt.Sprintf("enum %s.%s was skipped; this dialect does not support enums", e.Schema, e.Name))
+	}
+	return nil, warnings
+}
+
+// stripUnsupportedFunctions drops function definitions entirely when caps
+// says the target dialect can't render them, returning the remaining
+// functions and a warning per dropped function instead of letting the
+// caller emit invalid SQL.
+func stripUnsupportedFunctions(caps Capabilities, functions []models.Function) ([]models.Function, []string) {
+	if caps.Functions {
+		return functions, nil
+	}
+	var warnings []string
+	for _, f := range functions {
+		warnings = append(warnings, fmt.Sprintf("function %s.%s was skipped; this dialect does not support functions", f.Schema, f.Name))
+	}
+	return nil, warnings
+}
+
+// stripUnsupportedTriggers drops trigger definitions entirely when caps
+// says the target dialect can't render them, returning the remaining
+// triggers and a warning per dropped trigger instead of letting the caller
+// emit invalid SQL.
+func stripUnsupportedTriggers(caps Capabilities, triggers []models.Trigger) ([]models.Trigger, []string) {
+	if caps.Triggers {
+		return triggers, nil
+	}
+	var warnings []string
+	for _, t := range triggers {
+		warnings = append(warnings, fmt.Sprintf("trigger %s on %s.%s was skipped; this dialect does not support triggers", t.Name, t.Schema, t.Table))
+	}
+	return nil, warnings
+}
+
+// stripUnsupportedSequences drops sequence definitions entirely when caps
+// says the target dialect can't render them, returning the remaining
+// sequences and a warning per dropped sequence instead of letting the
+// caller emit invalid SQL.
+func stripUnsupportedSequences(caps Capabilities, sequences []models.Sequence) ([]models.Sequence, []string) {
+	if caps.Sequences {
+		return sequences, nil
+	}
+	var warnings []string
+	for _, s := range sequences {
+		warnings = append(warnings, fmt.Sprintf("sequence %s.%s was skipped; this dialect does not support sequences", s.Schema, s.Name))
+	}
+	return nil, warnings
+}
+
+// stripUnsupportedTableFeatures drops a table's LOCALITY, PARTITION BY, and
+// row-level TTL storage directives, and any columns' computed-column
+// expressions, when caps says the target dialect can't render them,
+// returning the adjusted table and a warning per stripped feature instead
+// of letting the caller emit invalid SQL.
+func stripUnsupportedTableFeatures(caps Capabilities, table models.Table) (models.Table, []string) {
+	if caps.Locality && caps.Partitioning && caps.RowLevelTTL && caps.ComputedColumns {
+		return table, nil
+	}
+
+	var warnings []string
+	if !caps.Locality && table.Locality != nil {
+		warnings = append(warnings, fmt.Sprintf("table %s.%s specifies a LOCALITY, which this dialect does not support; it was dropped", table.Schema, table.Name))
+		table.Locality = nil
+	}
+	if !caps.Partitioning && table.Partitioning != nil {
+		warnings = append(warnings, fmt.Sprintf("table %s.%s is partitioned, which this dialect does not support; the partitioning was dropped", table.Schema, table.Name))
+		table.Partitioning = nil
+	}
+	if !caps.RowLevelTTL && table.TTL != nil {
+		warnings = append(warnings, fmt.Sprintf("table %s.%s has row-level TTL configured, which this dialect does not support; it was dropped", table.Schema, table.Name))
+		table.TTL = nil
+	}
+	if !caps.ComputedColumns {
+		adjusted := make([]models.Column, len(table.Columns))
+		for i, col := range table.Columns {
+			if col.Computed != nil {
+				warnings = append(warnings, fmt.Sprintf("column %s.%s.%s is computed, which this dialect does not support; its expression was dropped", table.Schema, table.Name, col.Name))
+				col.Computed = nil
+			}
+			adjusted[i] = col
+		}
+		table.Columns = adjusted
+	}
+	return table, warnings
+}
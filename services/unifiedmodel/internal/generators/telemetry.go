@@ -0,0 +1,110 @@
+package generators
+
+import "github.com/redbco/redb-open/services/unifiedmodel/internal/models"
+
+// Report carries feature-usage telemetry for a single GenerateSchema call,
+// modeled on CockroachDB's own per-feature telemetry counters. Counter keys
+// are dotted paths such as "tables.created", "indexes.include", or
+// "constraints.foreign_key.on_delete.cascade" so downstream services can
+// aggregate them without parsing SQL.
+type Report struct {
+	Telemetry map[string]int
+}
+
+// newReport returns an empty Report ready to record counters.
+func newReport() *Report {
+	return &Report{Telemetry: make(map[string]int)}
+}
+
+// incr increments key by delta, creating the counter if it doesn't exist
+// yet. delta is typically 1, but callers tally multi-valued features (e.g.
+// enum values) in one call.
+func (r *Report) incr(key string, delta int) {
+	if delta == 0 {
+		return
+	}
+	r.Telemetry[key] += delta
+}
+
+// Merge adds other's counters into r, for combining reports from multiple
+// GenerateSchema calls (e.g. across dialects, or across a migration's up
+// and down scripts).
+func (r *Report) Merge(other *Report) {
+	if other == nil {
+		return
+	}
+	for k, v := range other.Telemetry {
+		r.incr(k, v)
+	}
+}
+
+// AggregateReports combines a set of per-dialect or per-call Reports into
+// one, so services like the unifiedmodel gRPC handlers can log or expose a
+// single feature-usage summary instead of one per call.
+func AggregateReports(reports ...*Report) *Report {
+	total := newReport()
+	for _, r := range reports {
+		total.Merge(r)
+	}
+	return total
+}
+
+// recordTableTelemetry tallies the schema constructs a single table
+// contributes: the table itself, its constraints by type/action, and its
+// indexes by feature (partial, INCLUDE, hash-sharded).
+func recordTableTelemetry(report *Report, table models.Table) {
+	report.incr("tables.created", 1)
+
+	for _, col := range table.Columns {
+		if col.DefaultValue != nil {
+			if col.DefaultIsFunction {
+				report.incr("columns.default.function", 1)
+			} else {
+				report.incr("columns.default.literal", 1)
+			}
+		}
+		if col.Computed != nil && col.Computed.Expression != "" {
+			report.incr("columns.computed", 1)
+		}
+	}
+
+	for _, constraint := range table.Constraints {
+		switch constraint.Type {
+		case "FOREIGN KEY":
+			report.incr("constraints.foreign_key", 1)
+			switch constraint.OnDelete {
+			case "CASCADE":
+				report.incr("constraints.foreign_key.on_delete.cascade", 1)
+			case "SET NULL":
+				report.incr("constraints.foreign_key.on_delete.set_null", 1)
+			}
+		case "UNIQUE":
+			report.incr("constraints.unique", 1)
+		case "CHECK":
+			report.incr("constraints.check", 1)
+		}
+	}
+
+	for _, index := range table.Indexes {
+		report.incr("indexes.created", 1)
+		if index.WhereClause != "" {
+			report.incr("indexes.partial", 1)
+		}
+		if len(index.IncludeColumns) > 0 {
+			report.incr("indexes.include", 1)
+		}
+		if index.HashSharded != nil && index.HashSharded.BucketCount > 0 {
+			report.incr("indexes.hash_sharded", 1)
+		}
+	}
+
+	if table.Locality != nil {
+		report.incr("tables.locality."+table.Locality.Type, 1)
+	}
+	if table.Partitioning != nil {
+		report.incr("tables.partitioning."+table.Partitioning.Type, 1)
+	}
+	if table.TTL != nil {
+		report.incr("tables.ttl", 1)
+	}
+}
@@ -0,0 +1,208 @@
+// Package models defines the dialect-agnostic schema representation
+// ("UnifiedModel") that per-database generators, such as the ones under
+// services/unifiedmodel/internal/generators, render into DDL or diff
+// against one another.
+package models
+
+// UnifiedModel is a complete, dialect-agnostic description of a database
+// schema: its namespaces, tables, and the standalone objects (enums,
+// functions, triggers, sequences, extensions) that live alongside them.
+type UnifiedModel struct {
+	Schemas    []Schema
+	Tables     []Table
+	Enums      []Enum
+	Functions  []Function
+	Triggers   []Trigger
+	Sequences  []Sequence
+	Extensions []Extension
+}
+
+// Schema is a namespace that tables and other objects are created within.
+type Schema struct {
+	Name         string
+	CharacterSet string
+	Collation    string
+}
+
+// Table describes a single table: its columns, constraints, indexes, and
+// any CockroachDB-native storage directives that apply to it.
+type Table struct {
+	Schema      string
+	Name        string
+	Columns     []Column
+	Constraints []Constraint
+	Indexes     []Index
+
+	// Locality, Partitioning, and TTL are CockroachDB-native storage
+	// features with no equivalent on every dialect; they are nil when the
+	// table doesn't use them.
+	Locality     *TableLocality
+	Partitioning *TablePartitioning
+	TTL          *TableTTL
+}
+
+// TableLocality describes a CockroachDB LOCALITY setting: REGIONAL BY ROW,
+// REGIONAL BY TABLE, or GLOBAL.
+type TableLocality struct {
+	// Type is "REGIONAL BY ROW", "REGIONAL BY TABLE", or "GLOBAL".
+	Type string
+	// Region is the home region for REGIONAL BY TABLE; empty means the
+	// database's primary region.
+	Region string
+	// RegionColumn is the discriminator column for REGIONAL BY ROW; empty
+	// means CRDB's default "crdb_region" column.
+	RegionColumn string
+}
+
+// TablePartitioning describes a PARTITION BY LIST/RANGE clause.
+type TablePartitioning struct {
+	// Type is "LIST" or "RANGE".
+	Type       string
+	Columns    []string
+	Partitions []TablePartition
+}
+
+// TablePartition is a single named partition within a TablePartitioning.
+type TablePartition struct {
+	Name string
+	// Values holds the literal VALUES IN (...) list for a LIST partition.
+	Values string
+	// From and To hold the literal VALUES FROM (...) TO (...) bounds for a
+	// RANGE partition.
+	From string
+	To   string
+	// Zone, if set, is the zone configuration this partition should be
+	// pinned to via a follow-up CONFIGURE ZONE statement.
+	Zone string
+}
+
+// TableTTL describes a CockroachDB row-level TTL configuration.
+type TableTTL struct {
+	// ExpireAfter is a CRDB interval string, e.g. "90 days"; generators
+	// render it as a quoted SQL literal.
+	ExpireAfter string
+	// JobCron is a crontab expression overriding the default TTL job schedule.
+	JobCron string
+}
+
+// Column describes a single table column.
+type Column struct {
+	Name              string
+	DataType          DataType
+	IsNullable        bool
+	IsPrimaryKey      bool
+	DefaultValue      *string
+	DefaultIsFunction bool
+	Collation         string
+
+	// Computed is non-nil when the column's value is derived from an
+	// expression rather than stored/supplied directly.
+	Computed *ComputedColumn
+}
+
+// ComputedColumn describes a generated column's expression and whether it
+// is persisted (STORED) or computed on read (VIRTUAL).
+type ComputedColumn struct {
+	Expression string
+	Stored     bool
+}
+
+// DataType names a column's underlying type, e.g. "INT8" or "VARCHAR(255)".
+type DataType struct {
+	Name string
+}
+
+// Constraint describes a table-level constraint: UNIQUE, CHECK, or FOREIGN KEY.
+type Constraint struct {
+	Type            string
+	Name            string
+	Columns         []string
+	CheckExpression string
+
+	ReferencedTable   string
+	ReferencedColumns []string
+	OnDelete          string
+	OnUpdate          string
+}
+
+// Index describes a secondary or unique index on a table.
+type Index struct {
+	Schema         string
+	Table          string
+	Name           string
+	IsUnique       bool
+	Columns        []IndexColumn
+	IncludeColumns []string
+	WhereClause    string
+
+	// HashSharded is non-nil when the index is hash-sharded for write
+	// distribution across ranges.
+	HashSharded *HashShardedIndex
+}
+
+// HashShardedIndex describes a CockroachDB USING HASH WITH BUCKET_COUNT index.
+type HashShardedIndex struct {
+	BucketCount int
+}
+
+// IndexColumn is a single column within an index, along with its sort
+// direction and NULL ordering.
+type IndexColumn struct {
+	ColumnName string
+	// Order is positive for ASC, negative for DESC, zero for the dialect default.
+	Order int
+	// NullPosition is positive for NULLS FIRST, negative for NULLS LAST,
+	// zero for the dialect default.
+	NullPosition int
+}
+
+// Enum is a named enumerated type and its ordered values.
+type Enum struct {
+	Schema string
+	Name   string
+	Values []string
+}
+
+// Function is a stored function definition.
+type Function struct {
+	Schema     string
+	Name       string
+	Arguments  []FunctionArgument
+	ReturnType string
+	Definition string
+}
+
+// FunctionArgument is a single named, typed function parameter.
+type FunctionArgument struct {
+	Name     string
+	DataType string
+}
+
+// Trigger is a row-level trigger attached to a table.
+type Trigger struct {
+	Schema     string
+	Table      string
+	Name       string
+	Timing     string
+	Event      string
+	Definition string
+}
+
+// Sequence is a standalone sequence generator.
+type Sequence struct {
+	Schema    string
+	Name      string
+	DataType  string
+	Start     int64
+	Increment int64
+	MinValue  int64
+	MaxValue  int64
+	CacheSize int64
+	Cycle     bool
+}
+
+// Extension is a database extension enabled within a schema.
+type Extension struct {
+	Schema string
+	Name   string
+}